@@ -0,0 +1,40 @@
+// Package chiadapter serves the wedyta UI by chi, for gin see the controller package.
+//
+//	s := service.NewService(db, cfg)
+//	r := chi.NewRouter()
+//	chiadapter.RegisterRoutes(r, s)
+package chiadapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pa-pe/wedyta/adapter/httpadapter"
+	"github.com/pa-pe/wedyta/service"
+)
+
+// RegisterRoutes adds the routes of the service, the static files and the uploads of WedytaConfig.FileUploadFolder to the router.
+// WedytaConfig.Template isn't supported, the pages are rendered by the wedyta templates.
+func RegisterRoutes(r chi.Router, s *service.Service) {
+	if s.UploadsConfigured {
+		uploadsPath := strings.TrimSuffix(s.Config.FileUploadRelativePath, "/")
+		r.Handle(uploadsPath+"/*", http.StripPrefix(uploadsPath, http.FileServer(http.Dir(s.Config.FileUploadFolder))))
+	}
+
+	r.Handle(service.StaticPath+"/*", http.StripPrefix(service.StaticPath, http.FileServer(http.FS(service.StaticFiles()))))
+	for _, route := range s.Routes() {
+		r.Method(route.Method, route.Path, routeHandler(route))
+	}
+}
+
+func routeHandler(route service.Route) http.Handler {
+	names := route.Params()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := make(map[string]string, len(names))
+		for _, name := range names {
+			params[name] = chi.URLParam(r, name)
+		}
+		httpadapter.Serve(w, r, params, route.Handler)
+	})
+}
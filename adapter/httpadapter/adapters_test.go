@@ -0,0 +1,102 @@
+package httpadapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/pa-pe/wedyta/adapter/chiadapter"
+	"github.com/pa-pe/wedyta/adapter/httpadapter"
+	"github.com/pa-pe/wedyta/controller"
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestAdapters runs the same requests through the gin, net/http and chi adapters
+func TestAdapters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newService := func(t *testing.T) *service.Service {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("failed to open sqlite test database: %v", err)
+		}
+		if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT); INSERT INTO items (name) VALUES ('apple');`).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+
+		return service.NewService(db, &model.WedytaConfig{
+			AssetsSource: model.AssetsSourceCDN,
+			ConfigFS: fstest.MapFS{
+				"items.json": {Data: []byte(`{"pageTitle": "Items", "fields": ["id", "name"], "editableFields": ["name"]}`)},
+			},
+			AccessCheckFunc: func(ctx *model.Request, modelName, fieldName, action string) bool {
+				return ctx.GetHeader("X-Deny") == ""
+			},
+		})
+	}
+
+	adapters := map[string]func(s *service.Service) http.Handler{
+		"gin": func(s *service.Service) http.Handler {
+			r := gin.New()
+			controller.NewController(s).RegisterRoutes(r)
+			return r
+		},
+		"net/http": func(s *service.Service) http.Handler {
+			return httpadapter.Handler(s)
+		},
+		"chi": func(s *service.Service) http.Handler {
+			r := chi.NewRouter()
+			chiadapter.RegisterRoutes(r, s)
+			return r
+		},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		deny     bool
+		status   int
+		contains string
+	}{
+		{name: "table", method: "GET", url: "/wedyta/items", status: http.StatusOK, contains: "apple"},
+		{name: "denied", method: "GET", url: "/wedyta/items", deny: true, status: http.StatusForbidden, contains: "Access Denied"},
+		{name: "static", method: "GET", url: "/wedyta/static/js/wedyta_i18n.js", status: http.StatusOK, contains: "wedytaT"},
+		{name: "update", method: "POST", url: "/wedyta/update", body: `{"modelName": "items", "id": 1, "name": "pear"}`, status: http.StatusOK, contains: `"success":true`},
+		{name: "record", method: "GET", url: "/wedyta/items/1", status: http.StatusOK, contains: "pear"},
+		{name: "record action", method: "GET", url: "/wedyta/items/1/update", status: http.StatusOK, contains: "pear"},
+		{name: "unknown action", method: "GET", url: "/wedyta/items/1/archive", status: http.StatusInternalServerError},
+		{name: "index", method: "GET", url: "/wedyta/", status: http.StatusOK, contains: `href="/wedyta/items"`},
+	}
+
+	for adapterName, newHandler := range adapters {
+		handler := newHandler(newService(t))
+		for _, tt := range tests {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.deny {
+				req.Header.Set("X-Deny", "1")
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("%s %s: expected status %d, got %d: %s", adapterName, tt.name, tt.status, w.Code, w.Body.String())
+				continue
+			}
+			if !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("%s %s: expected %q in the response", adapterName, tt.name, tt.contains)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package httpadapter serves the wedyta UI by net/http, for gin see the controller package.
+//
+//	s := service.NewService(db, cfg)
+//	mux := http.NewServeMux()
+//	mux.Handle("/wedyta/", httpadapter.Handler(s))
+package httpadapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/service"
+)
+
+// Handler serves the routes of the service, the static files and the uploads of WedytaConfig.FileUploadFolder.
+// WedytaConfig.Template isn't supported, the pages are rendered by the wedyta templates.
+func Handler(s *service.Service) http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range s.Routes() {
+		mux.Handle(route.Method+" "+muxPattern(route.Path), routeHandler(route))
+	}
+
+	static := http.StripPrefix(service.StaticPath, http.FileServer(http.FS(service.StaticFiles())))
+
+	var uploads http.Handler
+	uploadsPrefix := strings.TrimSuffix(s.Config.FileUploadRelativePath, "/") + "/"
+	if s.UploadsConfigured {
+		uploads = http.StripPrefix(strings.TrimSuffix(uploadsPrefix, "/"), http.FileServer(http.Dir(s.Config.FileUploadFolder)))
+	}
+
+	// the prefixes aren't patterns of the mux as they would conflict with /wedyta/{modelName}/{recID}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, service.StaticPath+"/"):
+			static.ServeHTTP(w, r)
+		case uploads != nil && strings.HasPrefix(r.URL.Path, uploadsPrefix):
+			uploads.ServeHTTP(w, r)
+		default:
+			mux.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Serve runs the Service handler with the route parameters and writes its response
+func Serve(w http.ResponseWriter, r *http.Request, params map[string]string, handler func(ctx *model.Request)) {
+	req := model.NewRequest(r, params)
+	handler(req)
+	req.Response.WriteTo(w)
+}
+
+func routeHandler(route service.Route) http.Handler {
+	names := route.Params()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := make(map[string]string, len(names))
+		for _, name := range names {
+			params[name] = r.PathValue(name)
+		}
+		Serve(w, r, params, route.Handler)
+	})
+}
+
+// muxPattern matches the trailing slash route exactly as gin does
+func muxPattern(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return path + "{$}"
+	}
+	return path
+}
@@ -0,0 +1,36 @@
+// Package dbopen opens a GORM database for the wedyta commands by driver name and DSN.
+package dbopen
+
+import (
+	"fmt"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const Drivers = "sqlite, mysql, postgres"
+
+// Open opens the database, driver is one of: sqlite, mysql, postgres
+func Open(driver, dsn string, debug bool) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case "sqlite", "sqlite3":
+		dialector = sqlite.Open(dsn)
+	case "mysql":
+		dialector = mysql.Open(dsn)
+	case "postgres", "postgresql":
+		dialector = postgres.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported driver %q, expected one of: %s", driver, Drivers)
+	}
+
+	logLevel := logger.Silent
+	if debug {
+		logLevel = logger.Info
+	}
+
+	return gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logLevel)})
+}
@@ -0,0 +1,70 @@
+// Command wedyta-gen introspects a database and writes starter wedyta model configs for its tables.
+//
+// Usage:
+//
+//	wedyta-gen -driver sqlite -dsn app.db -out config/wedyta
+//	wedyta-gen -driver postgres -dsn "host=localhost user=app dbname=app" -tables users,orders -force
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pa-pe/wedyta/cmd/internal/dbopen"
+	"github.com/pa-pe/wedyta/generator"
+)
+
+func main() {
+	driver := flag.String("driver", "sqlite", "database driver: "+dbopen.Drivers)
+	dsn := flag.String("dsn", "", "data source name, e.g. a path to the sqlite file")
+	out := flag.String("out", "config/wedyta", "folder for the generated configs")
+	tables := flag.String("tables", "", "comma separated list of tables, all tables if empty")
+	exclude := flag.String("exclude", "wedyta_audit", "comma separated list of tables to skip")
+	force := flag.Bool("force", false, "overwrite existing config files")
+	debug := flag.Bool("debug", false, "log SQL queries")
+	flag.Parse()
+
+	if *dsn == "" {
+		fmt.Fprintln(os.Stderr, "wedyta-gen: -dsn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := dbopen.Open(*driver, *dsn, *debug)
+	if err != nil {
+		log.Fatalf("wedyta-gen: can't open database: %v", err)
+	}
+
+	configs, err := generator.GenerateModelConfigs(db, generator.Options{
+		Tables:        splitList(*tables),
+		ExcludeTables: splitList(*exclude),
+	})
+	if err != nil {
+		log.Fatalf("wedyta-gen: %v", err)
+	}
+
+	written, err := generator.WriteModelConfigs(*out, configs, *force)
+	if err != nil {
+		log.Fatalf("wedyta-gen: %v", err)
+	}
+
+	for _, path := range written {
+		fmt.Println(path)
+	}
+	if skipped := len(configs) - len(written); skipped > 0 {
+		fmt.Printf("%d existing config(s) skipped, use -force to overwrite\n", skipped)
+	}
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Command wedyta-lint validates wedyta model configs against a database and exits with status 1 if any issue is found.
+//
+// Usage:
+//
+//	wedyta-lint -driver sqlite -dsn app.db -config config/wedyta
+//	wedyta-lint -driver mysql -dsn "user:pass@tcp(localhost:3306)/app" -json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pa-pe/wedyta/cmd/internal/dbopen"
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/service"
+)
+
+func main() {
+	driver := flag.String("driver", "sqlite", "database driver: "+dbopen.Drivers)
+	dsn := flag.String("dsn", "", "data source name, e.g. a path to the sqlite file")
+	configDir := flag.String("config", "config/wedyta", "folder with the model configs")
+	jsonOutput := flag.Bool("json", false, "print the issues as JSON")
+	debug := flag.Bool("debug", false, "log SQL queries")
+	flag.Parse()
+
+	if *dsn == "" {
+		fmt.Fprintln(os.Stderr, "wedyta-lint: -dsn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := dbopen.Open(*driver, *dsn, *debug)
+	if err != nil {
+		log.Fatalf("wedyta-lint: can't open database: %v", err)
+	}
+
+	// the pages aren't rendered, so the assets don't have to be vendored
+	s, err := service.NewServiceE(db, &model.WedytaConfig{ConfigDir: *configDir, AssetsSource: model.AssetsSourceCDN})
+	if err != nil {
+		log.Fatalf("wedyta-lint: %v", err)
+	}
+	issues := s.Validate()
+
+	if *jsonOutput {
+		if issues == nil {
+			issues = model.ConfigIssues{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(issues); err != nil {
+			log.Fatalf("wedyta-lint: %v", err)
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue.String())
+		}
+	}
+
+	if len(issues) > 0 {
+		if !*jsonOutput {
+			fmt.Fprintf(os.Stderr, "%d issue(s) found\n", len(issues))
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,147 @@
+// Command wedyta serves the wedyta UI over a database without a host application.
+// The model configs are generated in memory for all tables unless -config is given, the models are listed on /wedyta/.
+//
+// Usage:
+//
+//	wedyta -dsn app.db
+//	wedyta -driver postgres -dsn "host=localhost user=app dbname=app" -addr :8080 -user admin -password secret
+//	WEDYTA_PASSWORD=secret wedyta -dsn app.db -config config/wedyta
+package main
+
+import (
+	"crypto/subtle"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pa-pe/wedyta/adapter/httpadapter"
+	"github.com/pa-pe/wedyta/cmd/internal/dbopen"
+	"github.com/pa-pe/wedyta/generator"
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/service"
+)
+
+type options struct {
+	driver    string
+	dsn       string
+	configDir string
+	tables    string
+	exclude   string
+	user      string
+	password  string
+	assets    string
+	debug     bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.driver, "driver", "sqlite", "database driver: "+dbopen.Drivers)
+	flag.StringVar(&opts.dsn, "dsn", "", "data source name, e.g. a path to the sqlite file")
+	flag.StringVar(&opts.configDir, "config", "", "folder with the model configs, generated in memory if empty")
+	flag.StringVar(&opts.tables, "tables", "", "comma separated list of tables of the generated configs, all tables if empty")
+	flag.StringVar(&opts.exclude, "exclude", "wedyta_audit", "comma separated list of tables to skip in the generated configs")
+	flag.StringVar(&opts.user, "user", "admin", "basic auth user")
+	flag.StringVar(&opts.password, "password", "", "basic auth password, default: $WEDYTA_PASSWORD, no auth if empty")
+	flag.StringVar(&opts.assets, "assets", model.AssetsSourceEmbedded, "where the pages load Bootstrap, jQuery and Summernote from: embedded or cdn")
+	flag.BoolVar(&opts.debug, "debug", false, "log SQL queries")
+	addr := flag.String("addr", "localhost:8080", "listen address")
+	flag.Parse()
+
+	if opts.dsn == "" {
+		fmt.Fprintln(os.Stderr, "wedyta: -dsn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if opts.password == "" {
+		opts.password = os.Getenv("WEDYTA_PASSWORD")
+	}
+	if opts.password == "" {
+		log.Printf("wedyta: no password is set, everyone who can reach %s can edit the database", *addr)
+	}
+
+	r, err := newServer(opts)
+	if err != nil {
+		log.Fatalf("wedyta: %v", err)
+	}
+
+	log.Printf("wedyta: serving on http://%s/", *addr)
+	log.Fatal(http.ListenAndServe(*addr, r))
+}
+
+// newServer opens the database and returns the handler serving the index page and the wedyta routes
+func newServer(opts options) (http.Handler, error) {
+	db, err := dbopen.Open(opts.driver, opts.dsn, opts.debug)
+	if err != nil {
+		return nil, fmt.Errorf("can't open database: %w", err)
+	}
+
+	cfg := &model.WedytaConfig{ConfigDir: opts.configDir, AssetsSource: opts.assets}
+	if opts.configDir == "" {
+		configs, err := generator.GenerateModelConfigs(db, generator.Options{
+			Tables:        splitList(opts.tables),
+			ExcludeTables: splitList(opts.exclude),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if cfg.ConfigFS, err = generator.ConfigFS(configs); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.password != "" {
+		cfg.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+			if !authorized(ctx.Request, opts) {
+				ctx.Header("WWW-Authenticate", basicRealm)
+				return false
+			}
+			return true
+		}
+	}
+
+	s, err := service.NewServiceE(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/wedyta/", httpadapter.Handler(s))
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/wedyta/", http.StatusFound)
+	})
+
+	if opts.password == "" {
+		return mux, nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the wedyta pages answer 403 without the credentials, the index pages answer 401 so the browser asks for them
+		if (r.URL.Path == "/" || r.URL.Path == "/wedyta/") && !authorized(r, opts) {
+			w.Header().Set("WWW-Authenticate", basicRealm)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+const basicRealm = `Basic realm="wedyta"`
+
+// authorized checks the basic auth credentials
+func authorized(r *http.Request, opts options) bool {
+	user, password, ok := r.BasicAuth()
+	return ok && subtle.ConstantTimeCompare([]byte(user), []byte(opts.user)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(opts.password)) == 1
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pa-pe/wedyta/cmd/internal/dbopen"
+)
+
+func TestServer_SQLiteFile(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "app.db")
+	db, err := dbopen.Open("sqlite", dsn, false)
+	if err != nil {
+		t.Fatalf("can't open database: %v", err)
+	}
+	for _, query := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL)`,
+		`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER REFERENCES users(id), title TEXT)`,
+		`INSERT INTO users (username) VALUES ('alice')`,
+		`INSERT INTO posts (user_id, title) VALUES (1, 'Hello world')`,
+	} {
+		if err := db.Exec(query).Error; err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+	}
+
+	r, err := newServer(options{driver: "sqlite", dsn: dsn, user: "admin", password: "secret", assets: "cdn"})
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		auth     bool
+		status   int
+		contains []string
+	}{
+		{name: "root without auth", method: "GET", url: "/", status: http.StatusUnauthorized},
+		{name: "root", method: "GET", url: "/", auth: true, status: http.StatusFound},
+		{name: "index without auth", method: "GET", url: "/wedyta/", status: http.StatusUnauthorized},
+		{name: "index", method: "GET", url: "/wedyta/", auth: true, status: http.StatusOK, contains: []string{`href="/wedyta/posts"`, `href="/wedyta/users"`}},
+		{name: "table without auth", method: "GET", url: "/wedyta/posts", status: http.StatusForbidden},
+		{name: "table", method: "GET", url: "/wedyta/posts", auth: true, status: http.StatusOK, contains: []string{"Hello world", "alice"}},
+		{name: "update", method: "POST", url: "/wedyta/update", body: `{"modelName": "posts", "id": 1, "title": "Updated"}`, auth: true, status: http.StatusOK},
+		{name: "updated record", method: "GET", url: "/wedyta/posts/1", auth: true, status: http.StatusOK, contains: []string{"Updated"}},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if tt.auth {
+			req.SetBasicAuth("admin", "secret")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+			continue
+		}
+		if tt.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected WWW-Authenticate header", tt.name)
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(w.Body.String(), s) {
+				t.Errorf("%s: expected %q in the response", tt.name, s)
+			}
+		}
+	}
+}
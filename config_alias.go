@@ -3,3 +3,11 @@ package wedyta
 import "github.com/pa-pe/wedyta/model"
 
 type Config = model.WedytaConfig
+
+type ModelOptions = model.ModelOptions
+
+type RecordAction = model.RecordAction
+
+type ColumnFunc = model.ColumnFunc
+
+type Column = model.Column
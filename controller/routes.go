@@ -1,48 +1,66 @@
 package controller
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pa-pe/wedyta/embed"
-	"io/fs"
-	"log"
+	"context"
 	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/service"
 )
 
+type ginContextKey struct{}
+
 func (c *Controller) RegisterRoutes(r *gin.Engine) {
 	s := c.Service
 	//	r.SetHTMLTemplate(loadTemplates())
 
-	if c.Service.Config.FileUploadFolder != "" && c.Service.Config.FileUploadRelativePath != "" {
-		r.Static(c.Service.Config.FileUploadRelativePath, c.Service.Config.FileUploadFolder)
-		c.Service.UploadsConfigured = true
+	if s.UploadsConfigured {
+		r.Static(s.Config.FileUploadRelativePath, s.Config.FileUploadFolder)
 	}
 
-	staticFiles, err := fs.Sub(embed.EmbeddedFiles, "static")
-	if err != nil {
-		log.Fatalf("failed to initialize static files: %v", err)
+	r.StaticFS(service.StaticPath, http.FS(service.StaticFiles()))
+	for _, route := range s.Routes() {
+		r.Handle(route.Method, ginPath(route), Handler(route.Handler))
 	}
+}
 
-	wedytaGroup := r.Group("/wedyta")
-	//wedytaGroup.StaticFS("/static", http.FS(embeddedFiles))
-	wedytaGroup.StaticFS("/static", http.FS(staticFiles))
-	wedytaGroup.GET("/:modelName", s.RenderTable)
-	wedytaGroup.GET("/:modelName/create", s.RenderTableRecordCreate)
-	wedytaGroup.GET("/:modelName/:recID", s.RenderTableRecord)
-	wedytaGroup.GET("/:modelName/:recID/:action", c.routeModelRecordAction)
-	wedytaGroup.POST("/create", s.HandleTableCreateRecord)
-	wedytaGroup.POST("/update", s.Update)
-	wedytaGroup.POST("/upload/check", c.handleUploadCheck)
-	wedytaGroup.POST("/upload/image", c.HandleImageUpload)
+// Handler adapts the Service handler to gin, pages of WedytaConfig.Template are rendered by the HTML renderer of the engine
+func Handler(handler func(ctx *model.Request)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		params := make(map[string]string, len(ctx.Params))
+		for _, param := range ctx.Params {
+			params[param.Key] = param.Value
+		}
+
+		req := model.NewRequest(ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), ginContextKey{}, ctx)), params)
+		req.Keys = ctx.Keys
+		req.RemoteIP = ctx.ClientIP()
+		handler(req)
+
+		if req.Response.Template != "" {
+			for key, values := range req.Response.Header {
+				ctx.Writer.Header()[key] = values
+			}
+			ctx.HTML(req.Response.Status, req.Response.Template, req.Response.TemplateData)
+			return
+		}
+		req.Response.WriteTo(ctx.Writer)
+	}
 }
 
-func (c *Controller) routeModelRecordAction(ctx *gin.Context) {
-	s := c.Service
-	action := ctx.Param("action")
+// GinContext returns the gin context of the request passed to the WedytaConfig callbacks by the gin adapter
+func GinContext(req *model.Request) (*gin.Context, bool) {
+	ctx, ok := req.Request.Context().Value(ginContextKey{}).(*gin.Context)
+	return ctx, ok
+}
 
-	switch action {
-	case "update":
-		s.RenderTableRecord(ctx)
-	default:
-		s.SomethingWentWrong(ctx, "RouteModelRecordAction: unknown action="+action)
+// ginPath converts the {name} parameters of the route to the :name ones of gin
+func ginPath(route service.Route) string {
+	path := route.Path
+	for _, name := range route.Params() {
+		path = strings.Replace(path, "{"+name+"}", ":"+name, 1)
 	}
+	return path
 }
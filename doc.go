@@ -3,6 +3,7 @@ Package wedyta displays GORM database content on the web via Gin without requiri
 
 This package is intended to be imported and used by other applications.
 The exported functions, such as New, are used externally and may not be referenced inside this module itself.
+Applications on net/http or chi serve the routes of service.NewService by the adapter/httpadapter or adapter/chiadapter packages.
 
 See https://github.com/pa-pe/wedyta for more information about wedyta.
 */
@@ -0,0 +1,73 @@
+package embed
+
+// Asset is a frontend dependency vendored into EmbeddedFiles by go generate, see vendor_assets.go
+type Asset struct {
+	// Name is the key of the asset in the templates, e.g. {{asset "bootstrap.css"}}, fonts loaded by the stylesheets have no tags
+	Name string
+	// Path of the vendored copy in EmbeddedFiles, served under /wedyta/
+	Path string
+	// CDN is the URL of the same file, used if WedytaConfig.AssetsSource is "cdn" and by the vendoring
+	CDN string
+	// Integrity is the subresource integrity of the CDN file, checked by the vendoring, empty if not pinned
+	Integrity string
+}
+
+// Assets are the frontend dependencies of the default templates, the fonts are placed where the stylesheets expect them
+var Assets = []Asset{
+	{
+		Name:      "bootstrap.css",
+		Path:      "static/vendor/bootstrap/5.3.7/bootstrap.min.css",
+		CDN:       "https://cdn.jsdelivr.net/npm/bootstrap@5.3.7/dist/css/bootstrap.min.css",
+		Integrity: "sha384-LN+7fdVzj6u52u30Kp6M/trliBMCMKTyK833zpbD+pXdCLuTusPj697FH4R/5mcr",
+	},
+	{
+		Name:      "bootstrap.js",
+		Path:      "static/vendor/bootstrap/5.3.7/bootstrap.bundle.min.js",
+		CDN:       "https://cdn.jsdelivr.net/npm/bootstrap@5.3.7/dist/js/bootstrap.bundle.min.js",
+		Integrity: "sha384-ndDqU0Gzau9qJ1lfW4pNLlhNTkCfHzAVBReH9diLvGRem5+R9g2FzA8ZGN954O5Q",
+	},
+	{
+		Name: "bootstrap-icons.css",
+		Path: "static/vendor/bootstrap-icons/1.11.3/bootstrap-icons.min.css",
+		CDN:  "https://cdn.jsdelivr.net/npm/bootstrap-icons@1.11.3/font/bootstrap-icons.min.css",
+	},
+	{
+		Path: "static/vendor/bootstrap-icons/1.11.3/fonts/bootstrap-icons.woff2",
+		CDN:  "https://cdn.jsdelivr.net/npm/bootstrap-icons@1.11.3/font/fonts/bootstrap-icons.woff2",
+	},
+	{
+		Path: "static/vendor/bootstrap-icons/1.11.3/fonts/bootstrap-icons.woff",
+		CDN:  "https://cdn.jsdelivr.net/npm/bootstrap-icons@1.11.3/font/fonts/bootstrap-icons.woff",
+	},
+	{
+		Name: "jquery.js",
+		Path: "static/vendor/jquery/3.7.1/jquery.min.js",
+		CDN:  "https://code.jquery.com/jquery-3.7.1.min.js",
+	},
+	{
+		Name: "summernote.css",
+		Path: "static/vendor/summernote/0.9.1/summernote-bs5.min.css",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.css",
+	},
+	{
+		Name: "summernote.js",
+		Path: "static/vendor/summernote/0.9.1/summernote-bs5.min.js",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.js",
+	},
+	{
+		Path: "static/vendor/summernote/0.9.1/font/summernote.woff2",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/font/summernote.woff2",
+	},
+	{
+		Path: "static/vendor/summernote/0.9.1/font/summernote.woff",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/font/summernote.woff",
+	},
+	{
+		Path: "static/vendor/summernote/0.9.1/font/summernote.ttf",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/font/summernote.ttf",
+	},
+	{
+		Path: "static/vendor/summernote/0.9.1/font/summernote.eot",
+		CDN:  "https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/font/summernote.eot",
+	},
+}
@@ -2,5 +2,16 @@ package embed
 
 import "embed"
 
-//go:embed static/* templates/default.tmpl
+//go:generate go run vendor_assets.go
+
+//go:embed static/* templates/*.tmpl locales/*.json
 var EmbeddedFiles embed.FS
+
+// TemplatesPattern matches the default templates in EmbeddedFiles: the page layout default.tmpl and the named templates of the markup
+const TemplatesPattern = "templates/*.tmpl"
+
+// LocalesPattern matches the embedded translations of the UI texts in EmbeddedFiles, named by locale, e.g. locales/ru.json
+const LocalesPattern = "locales/*.json"
+
+// ModelConfigSchemaPath is the JSON Schema of the model configs in EmbeddedFiles, also served as /wedyta/static/schema/model-config.schema.json
+const ModelConfigSchemaPath = "static/schema/model-config.schema.json"
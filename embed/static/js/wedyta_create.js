@@ -1,45 +1,50 @@
-document.addEventListener("DOMContentLoaded", function () {
-    const addForm = document.getElementById("addForm");
-    if (addForm) {
-        addForm.addEventListener("submit", function (event) {
-            event.preventDefault();
+// the script is included by every add form of the page, e.g. of the child tables, the forms are bound once
+if (!window.wedytaCreateLoaded) {
+    window.wedytaCreateLoaded = true;
+    document.addEventListener("DOMContentLoaded", function () {
+        document.querySelectorAll("form.wedyta-add-form, form#addForm").forEach(bindAddForm);
+    });
+}
 
-            const formData = new FormData(addForm);
-            const formObject = {};
-            formData.forEach((value, key) => {
-                formObject[key] = value;
-            });
+function bindAddForm(addForm) {
+    addForm.addEventListener("submit", function (event) {
+        event.preventDefault();
 
-            fetch("/wedyta/create", {
-                method: "POST",
-                headers: {
-                    "Content-Type": "application/json"
-                },
-                body: JSON.stringify(formObject)
-            })
-                .then(response => response.json())
-                // .then(response => {
-                //     if (!response.ok) {
-                //         throw new Error("HTTP error " + response.status);
-                //     }
-                //     return response.json();
-                // })
-                .then(data => {
-                    if (data.success) {
-                        alert("Record created successfully");
-                        if (data.successfullyCreatedDestination === "refresh_page") {
-                            // location.reload();
-                            window.location.href = window.location.pathname + window.location.search + window.location.hash;
-                        } else if (data.successfullyCreatedDestination.startsWith("/")) {
-                            window.location.href = data.successfullyCreatedDestination;
-                        }
-                    } else {
-                        alert("Failed to create record: " + (data.error || "Unknown error"));
-                    }
-                })
-                .catch(error => {
-                    alert("Error: " + error);
-                });
+        const formData = new FormData(addForm);
+        const formObject = {};
+        formData.forEach((value, key) => {
+            formObject[key] = value;
         });
-    }
-});
+
+        fetch("/wedyta/create", {
+            method: "POST",
+            headers: {
+                "Content-Type": "application/json"
+            },
+            body: JSON.stringify(formObject)
+        })
+            .then(response => response.json())
+            // .then(response => {
+            //     if (!response.ok) {
+            //         throw new Error("HTTP error " + response.status);
+            //     }
+            //     return response.json();
+            // })
+            .then(data => {
+                if (data.success) {
+                    alert(wedytaT("Record created successfully"));
+                    if (data.successfullyCreatedDestination === "refresh_page") {
+                        // location.reload();
+                        window.location.href = window.location.pathname + window.location.search + window.location.hash;
+                    } else if (data.successfullyCreatedDestination.startsWith("/")) {
+                        window.location.href = data.successfullyCreatedDestination;
+                    }
+                } else {
+                    alert(wedytaT("Failed to create record: {error}", {error: wedytaT(data.error || "Unknown error")}));
+                }
+            })
+            .catch(error => {
+                alert(wedytaT("Error: {error}", {error: error}));
+            });
+    });
+}
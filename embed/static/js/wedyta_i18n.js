@@ -0,0 +1,8 @@
+// wedytaT translates the text by window.wedytaMessages of the page locale, {name} placeholders are replaced by params
+function wedytaT(text, params = {}) {
+    let translated = (window.wedytaMessages && window.wedytaMessages[text]) || text;
+    for (const name in params) {
+        translated = translated.split('{' + name + '}').join(params[name]);
+    }
+    return translated;
+}
@@ -26,7 +26,7 @@ function initSummernote(field, modelName, options = {}, extraOptions = {}) {
                         const text = await response.text();
 
                         if (!response.ok) {
-                            let message = "Upload failed.";
+                            let message = wedytaT("Upload failed.");
 
                             try {
                                 const parsed = JSON.parse(text);
@@ -83,8 +83,8 @@ async function isImageUploadAllowed(field, modelName, recordId) {
     if (!recordId) {
         return {
             allowed: false,
-            message: "It looks like you are currently in the new post creation mode.\n\n" +
-                "To enable uploading, please save the current post and continue editing and uploading the image from the edit mode."
+            message: wedytaT("It looks like you are currently in the new post creation mode.\n\n" +
+                "To enable uploading, please save the current post and continue editing and uploading the image from the edit mode.")
         };
     }
 
@@ -100,7 +100,7 @@ async function isImageUploadAllowed(field, modelName, recordId) {
         if (!response.ok) {
             return {
                 allowed: false,
-                message: `Server responded with error ${response.status} (${response.statusText})`
+                message: wedytaT("Server responded with error {status} ({statusText})", {status: response.status, statusText: response.statusText})
             };
         }
 
@@ -109,17 +109,17 @@ async function isImageUploadAllowed(field, modelName, recordId) {
         if (typeof result.allowed !== "boolean") {
             return {
                 allowed: false,
-                message: "Server response malformed: missing 'allowed' field."
+                message: wedytaT("Server response malformed: missing 'allowed' field.")
             };
         }
 
         return {
             allowed: result.allowed,
-            message: result.message || (result.allowed ? "" : "Server denied image upload.")
+            message: result.message || (result.allowed ? "" : wedytaT("Server denied image upload."))
         };
     } catch (err) {
         console.error("Upload check error:", err);
-        return {allowed: false, message: "Unable to verify permission with server. Please try again later."};
+        return {allowed: false, message: wedytaT("Unable to verify permission with server. Please try again later.")};
     }
 }
 
@@ -139,7 +139,7 @@ function showBootstrapPopup(message, type = 'danger') {
     <div id="${toastId}" class="toast align-items-center text-bg-${type} border-0 mb-2" role="alert" aria-live="assertive" aria-atomic="true">
       <div class="d-flex">
         <div class="toast-body">${message}</div>
-        <button type="button" class="btn-close btn-close-white me-2 m-auto" data-bs-dismiss="toast" aria-label="Close"></button>
+        <button type="button" class="btn-close btn-close-white me-2 m-auto" data-bs-dismiss="toast" aria-label="${wedytaT('Close')}"></button>
       </div>
     </div>
   `);
@@ -16,20 +16,95 @@ async function send_update_data(data, pageRefresh = true) {
         const result = await response.json();
 
         if (result.success) {
+            if (result.version) {
+                updateRecordVersion(data.modelName, data.id, result.version);
+            }
             if (pageRefresh) {
                 window.location.href = window.location.pathname + window.location.search + window.location.hash;
             }
             return true;
+        } else if (response.status === 409 && result.conflict) {
+            showConflictModal(data, result);
+            return false;
         } else {
-            alert('Failed to update: ' + (result.error || 'Unknown error'));
+            alert(wedytaT('Failed to update: {error}', {error: wedytaT(result.error || 'Unknown error')}));
             return false;
         }
     } catch (error) {
-        alert('Error: ' + error);
+        alert(wedytaT('Error: {error}', {error: error}));
         return false;
     }
 }
 
+function escapeHtml(text) {
+    return $('<div>').text(text === undefined || text === null ? '' : String(text)).html();
+}
+
+// version of the record for the optimistic concurrency control, see "versionField" of the model config,
+// undefined if the model has no version, the empty version is sent for the record which hasn't got it yet
+function takeRecordVersion($element) {
+    return $element.closest('[record_version]').attr('record_version');
+}
+
+function updateRecordVersion(modelName, recId, version) {
+    $('table[model="' + modelName + '"][record_id="' + recId + '"]').attr('record_version', version);
+    $('#editForm input[name="_version"]').val(version);
+    $('table[model="' + modelName + '"] tr[record_version]').each(function () {
+        if ($(this).find('.rec_id').text().trim() === String(recId)) {
+            $(this).attr('record_version', version);
+        }
+    });
+}
+
+// the record was changed by another user: show the current values and let the user overwrite them or reload the page
+function showConflictModal(data, result) {
+    $('#conflictModal').remove();
+
+    let rows = '';
+    for (const field in result.current) {
+        const header = (result.headers && result.headers[field]) || field;
+        const yours = data[field] !== undefined ? data[field] : '';
+        rows += `<tr><th>${escapeHtml(header)}</th><td>${escapeHtml(result.current[field])}</td><td>${escapeHtml(yours)}</td></tr>`;
+    }
+
+    $('body').append(`
+        <div class="modal fade" id="conflictModal" tabindex="-1" aria-hidden="true">
+            <div class="modal-dialog modal-dialog-centered modal-lg">
+                <div class="modal-content">
+                    <div class="modal-header">
+                        <h5 class="modal-title">${wedytaT('Conflict')}</h5>
+                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="${wedytaT('Close')}"></button>
+                    </div>
+                    <div class="modal-body">
+                        <p>${escapeHtml(wedytaT(result.error))}</p>
+                        <table class="table table-sm">
+                            <thead><tr><th></th><th>${wedytaT('Current value')}</th><th>${wedytaT('Your value')}</th></tr></thead>
+                            <tbody>${rows}</tbody>
+                        </table>
+                    </div>
+                    <div class="modal-footer">
+                        <button type="button" class="btn btn-secondary" id="conflictReloadBtn">${wedytaT('Reload')}</button>
+                        <button type="button" class="btn btn-danger" id="conflictOverwriteBtn">${wedytaT('Overwrite')}</button>
+                    </div>
+                </div>
+            </div>
+        </div>
+    `);
+
+    const modal = new bootstrap.Modal(document.getElementById('conflictModal'));
+    modal.show();
+
+    $('#conflictReloadBtn').on('click', function () {
+        window.location.href = window.location.pathname + window.location.search + window.location.hash;
+    });
+
+    $('#conflictOverwriteBtn').on('click', function () {
+        modal.hide();
+        const overwriteData = Object.assign({}, data, {_version: result.version});
+        send_update_data(overwriteData, true);
+    });
+}
+
 function showQueuedAnim(name, tagCont, doFunc) {
     if ($(tagCont).hasClass("change_animation_progress")) {
         setTimeout(showQueuedAnim, 100, name, tagCont, doFunc);
@@ -103,14 +178,14 @@ function createModal(title, contentHtml) {
                 <div class="modal-content">
                     <div class="modal-header">
                         <h5 class="modal-title" id="editModalLabel">${title}</h5>
-                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="Close"></button>
+                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="${wedytaT('Close')}"></button>
                     </div>
                     <div class="modal-body">
                         ${contentHtml}
                     </div>
                     <div class="modal-footer">
-                        <button type="button" class="btn btn-secondary" data-bs-dismiss="modal">Cancel</button>
-                        <button type="button" class="btn btn-primary" id="saveButton">Save</button>
+                        <button type="button" class="btn btn-secondary" data-bs-dismiss="modal">${wedytaT('Cancel')}</button>
+                        <button type="button" class="btn btn-primary" id="saveButton">${wedytaT('Save')}</button>
                     </div>
                 </div>
             </div>
@@ -165,13 +240,15 @@ function urlParamsToHiddenInputs() {
     return inputs;
 }
 
-function buildRecordUpdateForm(modelName, recordId, fieldName, content, isTextarea) {
+function buildRecordUpdateForm(modelName, recordId, fieldName, content, isTextarea, version) {
     const hiddenInputs = urlParamsToHiddenInputs();
+    const versionInput = version !== undefined ? `<input type="hidden" name="_version" value="${version}">` : '';
 
     return `
         <form id="editForm">
         <input type="hidden" name="modelName" value="${modelName}">
         <input type="hidden" name="id" value="${recordId}">
+        ${versionInput}
         ${hiddenInputs}
     ${isTextarea
         ? `<textarea class="form-control" name="${fieldName}" rows="5">${content}</textarea>`
@@ -192,12 +269,12 @@ function showConfirmModal(title, messageHtml, onConfirm) {
                 <div class="modal-content">
                     <div class="modal-header">
                         <h5 class="modal-title">${title}</h5>
-                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="Закрыть"></button>
+                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="${wedytaT('Close')}"></button>
                     </div>
                     <div class="modal-body">${messageHtml}</div>
                     <div class="modal-footer">
-                        <button type="button" class="btn btn-secondary" data-bs-dismiss="modal">Отмена</button>
-                        <button type="button" class="btn btn-primary" id="confirmModalBtn">Подтвердить</button>
+                        <button type="button" class="btn btn-secondary" data-bs-dismiss="modal">${wedytaT('Cancel')}</button>
+                        <button type="button" class="btn btn-primary" id="confirmModalBtn">${wedytaT('Confirm')}</button>
                     </div>
                 </div>
             </div>
@@ -221,10 +298,10 @@ function handleSwitchChange($checkbox) {
     $pendingCheckbox = $checkbox;
 
     showConfirmModal(
-        'Confirm Change',
+        wedytaT('Confirm Change'),
         checked
-            ? 'Are you sure you want to <strong>enable</strong> this record?'
-            : 'Are you sure you want to <strong>disable</strong> this record?',
+            ? wedytaT('Are you sure you want to <strong>enable</strong> this record?')
+            : wedytaT('Are you sure you want to <strong>disable</strong> this record?'),
         function () {
             applySwitchChangeConfirmed($pendingCheckbox, row, checked);
         }
@@ -242,6 +319,11 @@ function applySwitchChangeConfirmed($checkbox, $row, isChecked) {
         [fieldName]: isChecked ? 1 : 0
     };
 
+    const version = takeRecordVersion($checkbox);
+    if (version !== undefined) {
+        data._version = version;
+    }
+
     send_update_data(data, false).then(success => {
         if (success) {
             if (isChecked) {
@@ -258,6 +340,168 @@ function applySwitchChangeConfirmed($checkbox, $row, isChecked) {
     $pendingCheckbox = null;
 }
 
+async function send_delete_data(data, url = '/wedyta/delete') {
+    try {
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify(data),
+        });
+
+        const result = await response.json();
+
+        if (result.success) {
+            return true;
+        } else {
+            alert(wedytaT('Failed: {error}', {error: wedytaT(result.error || 'Unknown error')}));
+            return false;
+        }
+    } catch (error) {
+        alert(wedytaT('Error: {error}', {error: error}));
+        return false;
+    }
+}
+
+function handleDeleteClick($control) {
+    const $table = $control.closest('table');
+    const modelName = $table.attr("model") || 'unknown_model';
+    const recId = $control.attr('rec_id');
+    const isRecordPage = !!$table.attr("record_id");
+
+    showConfirmModal(
+        wedytaT('Confirm Delete'),
+        wedytaT('Are you sure you want to <strong>delete</strong> record #{id}?', {id: recId}),
+        function () {
+            send_delete_data({modelName: modelName, id: recId}).then(success => {
+                if (!success) {
+                    return;
+                }
+                if (isRecordPage) {
+                    window.location.href = '/wedyta/' + modelName + window.location.search;
+                } else {
+                    window.location.href = window.location.pathname + window.location.search + window.location.hash;
+                }
+            });
+        }
+    );
+}
+
+// restore or purge of the soft deleted record in the trash view
+function handleTrashAction($control, url, title, messageHtml) {
+    const modelName = $control.closest('table').attr("model") || 'unknown_model';
+    const recId = $control.attr('rec_id');
+
+    showConfirmModal(wedytaT(title), wedytaT(messageHtml, {id: recId}), function () {
+        send_delete_data({modelName: modelName, id: recId}, url).then(success => {
+            if (success) {
+                window.location.href = window.location.pathname + window.location.search + window.location.hash;
+            }
+        });
+    });
+}
+
+// named action of "recordActions" registered by the application
+function handleRecordAction($control) {
+    const modelName = $control.closest('table').attr("model") || 'unknown_model';
+    const recId = $control.attr('rec_id');
+    const confirm = $control.attr('confirm');
+
+    const execute = function () {
+        send_delete_data({modelName: modelName, action: $control.attr('action'), id: recId}, '/wedyta/action').then(success => {
+            if (success) {
+                window.location.href = window.location.pathname + window.location.search + window.location.hash;
+            }
+        });
+    };
+
+    if (confirm) {
+        showConfirmModal(wedytaT('Confirm Action'), wedytaT(escapeHtml(confirm), {id: recId}), execute);
+    } else {
+        execute();
+    }
+}
+
+// bulk actions on the rows selected by the checkboxes, see "bulkActions" of the model config
+function bulkTableOf($form) {
+    return $form.nextAll('table.table-model-records').first();
+}
+
+function selectedBulkIds($table) {
+    return $table.find('.wedyta-bulk-select:checked').map(function () {
+        return $(this).val();
+    }).get();
+}
+
+function updateBulkForm($form) {
+    const count = selectedBulkIds(bulkTableOf($form)).length;
+    $form.find('.wedyta-bulk-count').text(count ? wedytaT('{count} selected', {count: count}) : '');
+    const isSetField = $form.find('[name="action"]').val() === 'setField';
+    $form.find('[name="field"], [name="value"]').toggleClass('d-none', !isSetField);
+}
+
+function handleBulkSubmit($form) {
+    const $table = bulkTableOf($form);
+    const ids = selectedBulkIds($table);
+    const $action = $form.find('[name="action"]');
+    if (!$action.val()) {
+        return;
+    }
+    if (ids.length === 0) {
+        alert(wedytaT('No records selected'));
+        return;
+    }
+
+    const data = Object.fromEntries(new URLSearchParams(window.location.search));
+    data.modelName = $table.attr('model');
+    data.action = $action.val();
+    data.ids = ids;
+    if (data.action === 'setField') {
+        data.field = $form.find('[name="field"]').val();
+        data.value = $form.find('[name="value"]').val();
+    }
+
+    showConfirmModal(
+        wedytaT('Confirm Bulk Action'),
+        wedytaT('Are you sure you want to apply <strong>{action}</strong> to {count} records?', {action: escapeHtml($action.find('option:selected').text()), count: ids.length}),
+        function () {
+            send_bulk_data(data);
+        }
+    );
+}
+
+async function send_bulk_data(data) {
+    try {
+        const response = await fetch('/wedyta/bulk', {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify(data),
+        });
+
+        const result = await response.json();
+
+        if (!response.ok) {
+            alert(wedytaT('Failed: {error}', {error: wedytaT(result.error || 'Unknown error')}));
+            return;
+        }
+
+        let summary = wedytaT('{succeeded} of {total} records processed', {succeeded: result.succeeded.length, total: data.ids.length});
+        result.failed.forEach(failure => {
+            summary += '\n#' + failure.id + ': ' + wedytaT(failure.error);
+        });
+        alert(summary);
+
+        if (result.succeeded.length > 0) {
+            window.location.href = window.location.pathname + window.location.search + window.location.hash;
+        }
+    } catch (error) {
+        alert(wedytaT('Error: {error}', {error: error}));
+    }
+}
+
 function restoreSwitchOnCancel() {
     if ($pendingCheckbox) {
         // Revert checkbox if modal was dismissed
@@ -291,7 +535,7 @@ $(document).ready(function () {
             title = "#" + recordId + " " + title;
         }
 
-        const formHtml = buildRecordUpdateForm(modelName, recordId, fieldName, content, isTextarea);
+        const formHtml = buildRecordUpdateForm(modelName, recordId, fieldName, content, isTextarea, takeRecordVersion(currentTd));
         createModal(title, formHtml);
 
         $('#editModal').modal('show').on('shown.bs.modal', function () {
@@ -307,6 +551,41 @@ $(document).ready(function () {
         handleSwitchChange($(this));
     });
 
+    $(document).on('click', '.record-control-delete', function () {
+        handleDeleteClick($(this));
+    });
+
+    $(document).on('click', '.record-control-restore', function () {
+        handleTrashAction($(this), '/wedyta/restore', 'Confirm Restore', 'Are you sure you want to <strong>restore</strong> record #{id}?');
+    });
+
+    $(document).on('click', '.record-control-purge', function () {
+        handleTrashAction($(this), '/wedyta/purge', 'Confirm Delete', 'Are you sure you want to <strong>permanently delete</strong> record #{id}?');
+    });
+
+    $(document).on('click', '.record-action', function () {
+        handleRecordAction($(this));
+    });
+
+    $(document).on('change', '.wedyta-bulk-select-all', function () {
+        const $table = $(this).closest('table');
+        $table.find('.wedyta-bulk-select').prop('checked', $(this).prop('checked'));
+        updateBulkForm($table.prevAll('form.wedyta-bulk-form').first());
+    });
+
+    $(document).on('change', '.wedyta-bulk-select', function () {
+        updateBulkForm($(this).closest('table').prevAll('form.wedyta-bulk-form').first());
+    });
+
+    $(document).on('change', '.wedyta-bulk-form [name="action"]', function () {
+        updateBulkForm($(this).closest('form'));
+    });
+
+    $(document).on('submit', '.wedyta-bulk-form', function (event) {
+        event.preventDefault();
+        handleBulkSubmit($(this));
+    });
+
     // On cancel - return the checkbox to its original state
     $(document).on('hidden.bs.modal', '#editModal', restoreSwitchOnCancel);
 });
@@ -0,0 +1,78 @@
+//go:build ignore
+
+// vendor_assets downloads the frontend dependencies listed in Assets into static/vendor, run by: go generate ./embed
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pa-pe/wedyta/embed"
+)
+
+func main() {
+	for _, asset := range embed.Assets {
+		if err := vendor(asset); err != nil {
+			log.Fatalf("can't vendor %s: %v", asset.CDN, err)
+		}
+		log.Printf("vendored %s", asset.Path)
+	}
+}
+
+func vendor(asset embed.Asset) error {
+	response, err := http.Get(asset.CDN)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if asset.Integrity != "" {
+		if err := checkIntegrity(data, asset.Integrity); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(asset.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(asset.Path, data, 0o644)
+}
+
+func checkIntegrity(data []byte, integrity string) error {
+	algorithm, expected, _ := strings.Cut(integrity, "-")
+
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported integrity %q", integrity)
+	}
+	h.Write(data)
+
+	if actual := base64.StdEncoding.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("integrity mismatch: expected %s, got %s-%s", integrity, algorithm, actual)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Package generator introspects a database and emits starter wedyta model configs for its tables.
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
+)
+
+const (
+	DateTimeFormat = "2006-01-02 15:04:05"
+	DateFormat     = "2006-01-02"
+	TimeFormat     = "15:04:05"
+)
+
+// ConfigFile is the content of a model config file, it has the same format as config/wedyta/*.json
+type ConfigFile struct {
+	PageTitle      string                      `json:"pageTitle,omitempty"`
+	DbTable        string                      `json:"dbTable"`
+	Fields         []string                    `json:"fields"`
+	Headers        map[string]string           `json:"headers,omitempty"`
+	DateTimeFields map[string]string           `json:"dateTimeFields,omitempty"`
+	RelatedData    map[string]any              `json:"relatedData,omitempty"`
+	AddableFields  []string                    `json:"addableFields,omitempty"`
+	EditableFields []string                    `json:"editableFields,omitempty"`
+	RequiredFields []string                    `json:"requiredFields,omitempty"`
+	Links          map[string]model.LinkConfig `json:"links,omitempty"`
+}
+
+type Options struct {
+	// Tables to generate configs for, all tables of the database if empty
+	Tables []string
+	// ExcludeTables are skipped, e.g. wedyta_audit
+	ExcludeTables []string
+}
+
+// GenerateModelConfigs returns the configs of the tables mapped by model name (the table name)
+func GenerateModelConfigs(db *gorm.DB, opts Options) (map[string]ConfigFile, error) {
+	tables := opts.Tables
+	if len(tables) == 0 {
+		var err error
+		tables, err = sqlutils.GetTableNames(db)
+		if err != nil {
+			return nil, fmt.Errorf("can't list tables: %w", err)
+		}
+	}
+
+	configs := make(map[string]ConfigFile)
+	for _, table := range tables {
+		if slices.Contains(opts.ExcludeTables, table) {
+			continue
+		}
+
+		cfg, err := GenerateModelConfig(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table, err)
+		}
+		configs[table] = cfg
+	}
+
+	return configs, nil
+}
+
+// GenerateModelConfig builds the config of the table from its schema:
+// headers from column names, editable and addable fields excluding the primary key,
+// required fields from NOT NULL columns without default, dateTimeFields for temporal columns
+// and relatedData from foreign keys.
+func GenerateModelConfig(db *gorm.DB, table string) (ConfigFile, error) {
+	schema, err := sqlutils.GetTableSchema(db, table)
+	if err != nil {
+		return ConfigFile{}, err
+	}
+	if len(schema) == 0 {
+		return ConfigFile{}, errors.New("table not found or has no columns")
+	}
+
+	foreignKeys, err := sqlutils.GetForeignKeys(db, table)
+	if err != nil {
+		return ConfigFile{}, fmt.Errorf("can't take foreign keys: %w", err)
+	}
+
+	cfg := ConfigFile{
+		PageTitle:      utils.HumanizeName(table),
+		DbTable:        table,
+		Headers:        make(map[string]string),
+		DateTimeFields: make(map[string]string),
+		RelatedData:    make(map[string]any),
+		Links:          make(map[string]model.LinkConfig),
+	}
+
+	for _, col := range schema {
+		cfg.Fields = append(cfg.Fields, col.Name)
+		cfg.Headers[col.Name] = utils.HumanizeName(col.Name)
+
+		if sqlutils.IsDateType(col.Type) {
+			cfg.DateTimeFields[col.Name] = DateFormat
+		} else if sqlutils.IsDateTimeType(col.Type) {
+			cfg.DateTimeFields[col.Name] = DateTimeFormat
+		} else if sqlutils.IsTimeType(col.Type) {
+			cfg.DateTimeFields[col.Name] = TimeFormat
+		}
+
+		if col.IsPrimaryKey {
+			cfg.Links[col.Name] = model.LinkConfig{Preset: "self"}
+			continue
+		}
+
+		cfg.AddableFields = append(cfg.AddableFields, col.Name)
+		cfg.EditableFields = append(cfg.EditableFields, col.Name)
+
+		if !col.IsNullable && col.DefaultValue == nil {
+			cfg.RequiredFields = append(cfg.RequiredFields, col.Name)
+		}
+	}
+
+	for _, fk := range foreignKeys {
+		related, err := guessRelatedData(db, fk)
+		if err != nil {
+			return ConfigFile{}, fmt.Errorf("can't guess relatedData for %s: %w", fk.Column, err)
+		}
+		cfg.RelatedData[fk.Column] = related
+	}
+
+	return cfg, nil
+}
+
+// guessRelatedData returns the short "table.valueField" form if the key references the primary key,
+// otherwise the extended form with keyField
+func guessRelatedData(db *gorm.DB, fk sqlutils.ForeignKey) (any, error) {
+	schema, err := sqlutils.GetTableSchema(db, fk.ReferencedTable)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := ""
+	var columns []string
+	for _, col := range schema {
+		columns = append(columns, col.Name)
+		if col.IsPrimaryKey && pk == "" {
+			pk = col.Name
+		}
+	}
+
+	keyField := fk.ReferencedColumn
+	if keyField == "" {
+		keyField = pk
+	}
+
+	valueField := utils.GuessLabelField(columns)
+	if valueField == "" {
+		for _, col := range schema {
+			if !col.IsPrimaryKey && (strings.Contains(col.Type, "char") || strings.Contains(col.Type, "text")) {
+				valueField = col.Name
+				break
+			}
+		}
+	}
+	if valueField == "" {
+		valueField = keyField
+	}
+
+	if keyField == pk {
+		return fk.ReferencedTable + "." + valueField, nil
+	}
+
+	return model.RelatedDataEntry{
+		Table:      fk.ReferencedTable,
+		ValueField: valueField,
+		KeyField:   keyField,
+	}, nil
+}
+
+// WriteModelConfigs writes the configs to dir as <modelName>.json, existing files are skipped unless overwrite is set.
+// Returns the paths of the written files.
+func WriteModelConfigs(dir string, configs map[string]ConfigFile, overwrite bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	modelNames := make([]string, 0, len(configs))
+	for modelName := range configs {
+		modelNames = append(modelNames, modelName)
+	}
+	slices.Sort(modelNames)
+
+	var written []string
+	for _, modelName := range modelNames {
+		path := filepath.Join(dir, modelName+".json")
+		if !overwrite {
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+		}
+
+		data, err := json.MarshalIndent(configs[modelName], "", "  ")
+		if err != nil {
+			return written, err
+		}
+
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// ConfigFS returns the configs as an in-memory file system of <modelName>.json files for WedytaConfig.ConfigFS
+func ConfigFS(configs map[string]ConfigFile) (fs.FS, error) {
+	fsys := make(fstest.MapFS, len(configs))
+	for modelName, config := range configs {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		fsys[modelName+".json"] = &fstest.MapFile{Data: data}
+	}
+	return fsys, nil
+}
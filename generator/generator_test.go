@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var testDBSQLite *gorm.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDBSQLite, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("failed to open sqlite test database: " + err.Error())
+	}
+
+	for _, query := range []string{
+		`CREATE TABLE web_users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE orders (
+			order_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL REFERENCES web_users(id),
+			amount NUMERIC,
+			comment TEXT,
+			delivery_date DATE
+		);`,
+	} {
+		if err := testDBSQLite.Exec(query).Error; err != nil {
+			panic("failed to create table: " + err.Error())
+		}
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestGenerateModelConfig_SQLite(t *testing.T) {
+	cfg, err := GenerateModelConfig(testDBSQLite, "orders")
+	if err != nil {
+		t.Fatalf("GenerateModelConfig failed: %v", err)
+	}
+
+	expected := ConfigFile{
+		PageTitle: "Orders",
+		DbTable:   "orders",
+		Fields:    []string{"order_id", "user_id", "amount", "comment", "delivery_date"},
+		Headers: map[string]string{
+			"order_id":      "Order id",
+			"user_id":       "User id",
+			"amount":        "Amount",
+			"comment":       "Comment",
+			"delivery_date": "Delivery date",
+		},
+		DateTimeFields: map[string]string{"delivery_date": DateFormat},
+		RelatedData:    map[string]any{"user_id": "web_users.username"},
+		AddableFields:  []string{"user_id", "amount", "comment", "delivery_date"},
+		EditableFields: []string{"user_id", "amount", "comment", "delivery_date"},
+		RequiredFields: []string{"user_id"},
+		Links:          map[string]model.LinkConfig{"order_id": {Preset: "self"}},
+	}
+
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cfg)
+	}
+}
+
+func TestGenerateModelConfig_SQLite_DefaultIsNotRequired(t *testing.T) {
+	cfg, err := GenerateModelConfig(testDBSQLite, "web_users")
+	if err != nil {
+		t.Fatalf("GenerateModelConfig failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.RequiredFields, []string{"username"}) {
+		t.Errorf("expected only username to be required, got %v", cfg.RequiredFields)
+	}
+	if cfg.DateTimeFields["created_at"] != DateTimeFormat {
+		t.Errorf("expected created_at in dateTimeFields, got %v", cfg.DateTimeFields)
+	}
+}
+
+func TestGenerateModelConfig_SQLite_TableNotExists(t *testing.T) {
+	if _, err := GenerateModelConfig(testDBSQLite, "non_existing_table"); err == nil {
+		t.Errorf("expected error for non-existing table, got nil")
+	}
+}
+
+func TestWriteModelConfigs(t *testing.T) {
+	configs, err := GenerateModelConfigs(testDBSQLite, Options{ExcludeTables: []string{"web_users"}})
+	if err != nil {
+		t.Fatalf("GenerateModelConfigs failed: %v", err)
+	}
+	if len(configs) != 1 {
+		t.Fatalf("expected 1 config, got %d", len(configs))
+	}
+
+	dir := t.TempDir()
+	written, err := WriteModelConfigs(dir, configs, false)
+	if err != nil {
+		t.Fatalf("WriteModelConfigs failed: %v", err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("expected 1 written file, got %v", written)
+	}
+
+	// the written file must be a valid model config
+	data, err := os.ReadFile(filepath.Join(dir, "orders.json"))
+	if err != nil {
+		t.Fatalf("can't read written config: %v", err)
+	}
+	var mConfig model.ConfigOfModel
+	if err := json.Unmarshal(data, &mConfig); err != nil {
+		t.Fatalf("written config is not a valid model config: %v", err)
+	}
+	if mConfig.RelatedData["user_id"].Table != "web_users" || mConfig.RelatedData["user_id"].ValueField != "username" {
+		t.Errorf("unexpected relatedData: %+v", mConfig.RelatedData)
+	}
+
+	written, err = WriteModelConfigs(dir, configs, false)
+	if err != nil {
+		t.Fatalf("WriteModelConfigs failed: %v", err)
+	}
+	if len(written) != 0 {
+		t.Errorf("expected existing config to be skipped, got %v", written)
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// AuditRecord is a row of the wedyta_audit table, created when WedytaConfig.AuditEnabled is set
+type AuditRecord struct {
+	ID        int64     `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index"`
+	User      string    `gorm:"size:255"`
+	Model     string    `gorm:"size:255;index:idx_wedyta_audit_record"`
+	DbTable   string    `gorm:"size:255"`
+	RecordID  int64     `gorm:"index:idx_wedyta_audit_record"`
+	Action    string    `gorm:"size:32"`
+	Changes   string    `gorm:"type:text"` // json encoded []FieldChange
+}
+
+func (AuditRecord) TableName() string {
+	return "wedyta_audit"
+}
+
+// FieldChange is the value of a field before and after create, update or delete
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "gorm.io/gorm"
+
+// BulkAction is a custom action on the rows selected in the table, see WedytaConfig.BulkActions
+type BulkAction struct {
+	// Title is the text of the action in the bulk actions menu, translated by WedytaConfig.Messages
+	Title string
+
+	// Apply is called for every selected record inside the transaction of the bulk request,
+	// an error fails the record only, the changes of the other records are committed
+	Apply func(context *Request, db *gorm.DB, table string, id int64) error
+}
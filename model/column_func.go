@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// ColumnFunc renders the cell of a virtual field, registered by WedytaConfig.ColumnFuncs or Service.RegisterColumnFunc and referenced by "columnDataFunc"
+type ColumnFunc func(column Column) template.HTML
+
+// Column is the cell rendered by a ColumnFunc
+type Column struct {
+	Request *Request
+	DB      *gorm.DB
+	Model   *ConfigOfModel
+	Field   string
+	Record  Record
+	Params  ColumnParams // "params" of the columnDataFunc config of the field
+}
+
+// Record is a row of the table mapped by column
+type Record map[string]interface{}
+
+// String returns the value of the column as text, empty for NULL
+func (r Record) String(column string) string {
+	switch value := r[column].(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Int64 returns the value of the column as a number, false for NULL or not a number
+func (r Record) Int64(column string) (int64, bool) {
+	switch value := r[column].(type) {
+	case int64:
+		return value, true
+	case int:
+		return int64(value), true
+	case float64:
+		return int64(value), true
+	default:
+		number, err := strconv.ParseInt(r.String(column), 10, 64)
+		return number, err == nil
+	}
+}
+
+// Bool returns the value of the column as a flag, e.g. of is_active: 1 and true are true
+func (r Record) Bool(column string) bool {
+	flag, err := strconv.ParseBool(r.String(column))
+	return err == nil && flag
+}
+
+// ColumnParams are the params of a column function from the model config
+type ColumnParams map[string]interface{}
+
+// String returns the param as text or the default value if it's not set
+func (p ColumnParams) String(name, defaultValue string) string {
+	value, exists := p[name]
+	if !exists || value == nil {
+		return defaultValue
+	}
+	return fmt.Sprint(value)
+}
+
+// Int returns the numeric param or the default value if it's not set or not a number
+func (p ColumnParams) Int(name string, defaultValue int) int {
+	switch value := p[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case string:
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return defaultValue
+}
+
+// Bool returns the boolean param or the default value if it's not set
+func (p ColumnParams) Bool(name string, defaultValue bool) bool {
+	switch value := p[name].(type) {
+	case bool:
+		return value
+	case string:
+		if flag, err := strconv.ParseBool(value); err == nil {
+			return flag
+		}
+	}
+	return defaultValue
+}
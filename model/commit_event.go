@@ -0,0 +1,22 @@
+package model
+
+import "net/http"
+
+// RequestSnapshot is a detached copy of the request data, safe to use after the response is written
+type RequestSnapshot struct {
+	Method   string
+	URL      string
+	Header   http.Header
+	ClientIP string
+	Keys     map[string]any
+}
+
+// CommitEvent is passed to WedytaConfig.AfterCommit once the transaction of create, update or delete is committed
+type CommitEvent struct {
+	ModelName string
+	Table     string
+	Action    string
+	RecordID  int64
+	Changes   []FieldChange
+	Request   RequestSnapshot
+}
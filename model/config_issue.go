@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigValidation modes of WedytaConfig.ConfigValidation
+const (
+	ConfigValidationOff         = "off"
+	ConfigValidationUnavailable = "unavailable"
+	ConfigValidationStrict      = "strict"
+)
+
+// ConfigIssue is a problem of a model config found by the validation
+type ConfigIssue struct {
+	ModelName string `json:"modelName"`
+	// Path is the key of the config, e.g. "editableFields[2]" or "relatedData.user_id", empty for the whole config
+	Path    string `json:"path"`
+	Message string `json:"message"`
+
+	// File, Line and Column point to the source of the issue, empty for registered models
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+func (i ConfigIssue) String() string {
+	location := ""
+	if i.File != "" && i.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d: ", i.File, i.Line, i.Column)
+	} else if i.File != "" {
+		location = i.File + ": "
+	}
+
+	if i.Path == "" {
+		return location + i.ModelName + ": " + i.Message
+	}
+	return location + i.ModelName + ": " + i.Path + ": " + i.Message
+}
+
+type ConfigIssues []ConfigIssue
+
+func (issues ConfigIssues) Error() string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ByModel groups the issues by model name
+func (issues ConfigIssues) ByModel() map[string]ConfigIssues {
+	grouped := make(map[string]ConfigIssues)
+	for _, issue := range issues {
+		grouped[issue.ModelName] = append(grouped[issue.ModelName], issue)
+	}
+	return grouped
+}
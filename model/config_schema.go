@@ -0,0 +1,213 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/pa-pe/wedyta/utils/jsonschema"
+)
+
+// ConfigJSONSchema returns the JSON Schema of the model config files, embedded as embed/static/schema/model-config.schema.json
+func ConfigJSONSchema() *jsonschema.Schema {
+	schema := jsonschema.Generate(reflect.TypeOf(ConfigOfModel{}))
+	schema.Schema = jsonschema.Draft
+	schema.Title = "WeDyTa model config"
+	return schema
+}
+
+func (ConfigOfModel) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"$schema":           "URL or path of this schema, used by editors",
+		"extends":           "Configs merged under this one, by name without extension, e.g. _common; files starting with _ are not models",
+		"fieldDefinitions":  "Reusable field settings mapped by definition name",
+		"fieldPresets":      "Field definitions applied to the fields, mapped by field",
+		"pageTitle":         "Title of the pages, default: model name",
+		"group":             "Group of the model on the index page /wedyta/",
+		"dbTable":           "Table of the model, default: model name in snake_case",
+		"sqlWhere":          "SQL condition applied to every query, may contain {{variables}} resolved by WedytaConfig.VariableResolver",
+		"fields":            "Fields in display order, table columns or virtual fields of columnDataFunc and countRelatedData",
+		"orderBy":           "ORDER BY clause of the table page",
+		"headers":           "Column headers mapped by field, default: field name",
+		"titles":            "Hints shown on hover of the headers, mapped by field",
+		"classes":           "CSS classes of the cells, mapped by field",
+		"displayMode":       "Pages where the field is shown, comma separated: table, record, update, create or * for all",
+		"dateTimeFields":    "Go time layout of temporal fields, e.g. 2006-01-02 15:04:05",
+		"relatedData":       "Fields referencing other tables, shown by the value of the related record and edited by select",
+		"addableFields":     "Fields filled on the create page",
+		"requiredFields":    "Fields which can't be empty on create and update",
+		"editableFields":    "Fields editable in place and on the update page",
+		"deletable":         "Enables deleting of the records",
+		"versionField":      "Integer or timestamp column used to detect concurrent edits",
+		"softDelete":        "Moves deleted records to the trash: true for deleted_at column or a column name",
+		"duplicate":         "Adds the duplicate action to stdRecordControls opening the create form prefilled from the record",
+		"bulkActions":       "Actions on the rows selected in the table: delete, enable, disable, setField or the names of WedytaConfig.BulkActions",
+		"recordActions":     "Names of the actions of WedytaConfig.RecordActions or Service.RegisterRecordAction, shown as buttons in the stdRecordControls column",
+		"fieldsEditor":      "Editors of the fields mapped by field",
+		"noZeroValueFields": "Fields which can't be zero on create",
+		"password":          "Password fields, the value is encrypted by WedytaConfig.EncryptPlainPasswordFunc and never shown",
+		"columnDataFunc":    "Virtual fields rendered by a function: stdRecordControls, dynamicColumnDataFunc or a function of WedytaConfig.ColumnFuncs or Service.RegisterColumnFunc",
+		"countRelatedData":  "Virtual fields showing the count of related records",
+		"links":             "Fields rendered as links",
+		"parent":            "Parent model, the records are shown as children of the parent record",
+		"children":          "Child models shown as tabs of tables under the record page, their parent must be this model",
+		"permissions":       "Actions permitted to roles, evaluated by the default AccessCheckFunc, * role applies to everyone",
+		"breadcrumb":        "Breadcrumb of the record on its pages and on the pages of the children",
+		"locales":           "Texts of the model in other locales, mapped by BCP 47 tag, e.g. ru or pt-BR",
+		"templates":         "Templates used instead of the default ones, mapped by the default name: table, record, record_fields, create, add_form, form_label, form_input, select, trash, history, breadcrumbs, pagination, accordion, tabs, i18n, default.tmpl",
+	}
+}
+
+func (FieldEditorConfig) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"type": {
+				Type:        "string",
+				Description: "Editor type, other keys are passed to the editor, e.g. summernote options",
+				Enum:        []interface{}{"input", "textarea", "select", "summernote", "bs5switch"},
+			},
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+		Required:             []string{"type"},
+	}
+}
+
+func (RelatedDataEntry) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{
+				Type:        "string",
+				Description: `"table.valueField" referencing the primary key, or "SELECT key_field, value_field FROM table ..."`,
+			},
+			{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"table":      {Type: "string", Description: "Related table"},
+					"valueField": {Type: "string", Description: "Column shown instead of the key"},
+					"keyField":   {Type: "string", Description: "Referenced column, default: primary key of the table"},
+					"orderBy":    {Type: "string", Description: "ORDER BY clause of the select options"},
+				},
+				AdditionalProperties: false,
+				Required:             []string{"table", "valueField"},
+			},
+		},
+	}
+}
+
+func (SoftDeleteConfig) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "boolean", Description: "true uses the " + SoftDeleteDefaultField + " column"},
+			{Type: "string", Description: "Column set to the deletion time"},
+		},
+	}
+}
+
+func (ColumnDataFuncConfig) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string", Description: "Name of the function"},
+			{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name":   {Type: "string", Description: "Name of the function"},
+					"params": {Type: "object", Description: "Params passed to the registered function"},
+				},
+				Required:             []string{"name"},
+				AdditionalProperties: false,
+			},
+		},
+	}
+}
+
+func (DuplicateConfig) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "boolean"},
+			{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"resetFields": {
+						Type:        "array",
+						Items:       &jsonschema.Schema{Type: "string"},
+						Description: "Fields left empty besides the primary key, the unique columns and the passwords",
+					},
+				},
+				AdditionalProperties: false,
+			},
+		},
+	}
+}
+
+func (StringList) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+func (FieldDefinition) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"header":         "Column header",
+		"title":          "Hint shown on hover of the header",
+		"class":          "CSS classes of the cells",
+		"displayMode":    "Pages where the field is shown, comma separated: table, record, update, create or * for all",
+		"dateTimeFormat": "Go time layout, e.g. 2006-01-02 15:04:05",
+		"editor":         "Editor of the field",
+		"relatedData":    "Reference to another table",
+		"password":       "The field is a password",
+		"editable":       "Adds the field to editableFields",
+		"addable":        "Adds the field to addableFields",
+		"required":       "Adds the field to requiredFields",
+	}
+}
+
+func (LinkConfig) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"preset":   {Type: "string", Description: "self links to the record page", Enum: []interface{}{"self"}},
+			"template": {Type: "string", Description: "URL with $column$ placeholders"},
+		},
+		AdditionalProperties: false,
+	}
+}
+
+func (CountRelatedDataConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"localFieldID":  "Column of this model matched with targetFieldID",
+		"table":         "Table of the counted records",
+		"targetFieldID": "Column of the counted table referencing this model",
+	}
+}
+
+func (ParentConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"modelName":            "Name of the parent model",
+		"localConnectionField": "Column of this model referencing the parent record",
+		"queryVariableName":    "URL query variable holding the parent record id",
+	}
+}
+
+func (PermissionConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"actions": "Permitted actions: read, create, update, delete, restore, purge or * for all",
+		"fields":  "Fields an action is limited to, mapped by action",
+	}
+}
+
+func (LocaleConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"pageTitle": "Title of the pages",
+		"group":     "Group of the model on the index page",
+		"headers":   "Column headers mapped by field",
+		"titles":    "Hints shown on hover of the headers, mapped by field",
+	}
+}
+
+func (BreadcrumbConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{
+		"labelField": "Column shown in the breadcrumbs of the record and child pages instead of the record id",
+	}
+}
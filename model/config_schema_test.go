@@ -0,0 +1,37 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/pa-pe/wedyta/embed"
+)
+
+var update = flag.Bool("update", false, "update the embedded JSON Schema of the model configs")
+
+// TestConfigJSONSchemaInSync keeps the embedded schema in sync with the model types, run `go test ./model -update` after changing them
+func TestConfigJSONSchemaInSync(t *testing.T) {
+	generated, err := json.MarshalIndent(ConfigJSONSchema(), "", "  ")
+	if err != nil {
+		t.Fatalf("can't marshal schema: %v", err)
+	}
+	generated = append(generated, '\n')
+
+	if *update {
+		if err := os.WriteFile("../embed/"+embed.ModelConfigSchemaPath, generated, 0644); err != nil {
+			t.Fatalf("can't write schema: %v", err)
+		}
+		return
+	}
+
+	embedded, err := embed.EmbeddedFiles.ReadFile(embed.ModelConfigSchemaPath)
+	if err != nil {
+		t.Fatalf("can't read embedded schema: %v", err)
+	}
+	if !bytes.Equal(embedded, generated) {
+		t.Errorf("embedded %s is out of date, run: go test ./model -update", embed.ModelConfigSchemaPath)
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+// ModelOptions are the settings of a model registered from a GORM struct by RegisterModel.
+//
+// The config is derived from the GORM schema: table name, columns, time columns as dateTimeFields,
+// gorm.DeletedAt as softDelete and belongs-to relations as relatedData.
+// Fields are described by the `wedyta` struct tag, settings are separated by semicolons like in the `gorm` tag:
+//
+//	Name     string `wedyta:"header:Full name;title:As in the passport;editable;addable;required"`
+//	Body     string `wedyta:"editor:summernote;displayMode:record,update,create"`
+//	Password string `wedyta:"password"`
+//	Secret   string `wedyta:"-"`
+//	OwnerID  uint   `wedyta:"related:username"` // valueField of the belongs-to relation
+//	Owner    User
+type ModelOptions struct {
+	// ModelName used in urls, default: the name of the struct
+	ModelName string
+
+	// Config holds the settings which can't be expressed by struct tags, e.g. pageTitle, sqlWhere, parent or permissions.
+	// Its non-empty values take precedence over the ones derived from the struct.
+	Config ConfigOfModel
+}
@@ -0,0 +1,22 @@
+package model
+
+import "gorm.io/gorm"
+
+// RecordAction is an action on a record of WedytaConfig.RecordActions or Service.RegisterRecordAction,
+// the models listing its name in "recordActions" show it as a button in the stdRecordControls column
+type RecordAction struct {
+	// Label is the text of the button, translated by WedytaConfig.Messages
+	Label string
+
+	// Icon is the Bootstrap Icons class of the button, e.g. "bi-send"
+	Icon string
+
+	// Confirm is the question asked before the action, {id} is replaced by the id of the record, no confirmation if empty
+	Confirm string
+
+	// Permission is the action checked by AccessCheckFunc with the empty field, default the name of the action
+	Permission string
+
+	// Handler is called inside a DB transaction, an error rolls the transaction back and is sent to the client
+	Handler func(context *Request, db *gorm.DB, table string, id int64) error
+}
@@ -0,0 +1,24 @@
+package model
+
+// RenderOptions are the settings of the fragments rendered by Service.RenderTableFragment, RenderRecordFragment and RenderCreateFormFragment
+// for embedding into the pages of the host application.
+type RenderOptions struct {
+	// Filters are the values the fields of the table records must be equal to, e.g. {"status": "active"},
+	// the fields must be listed in the "fields" of the model config
+	Filters map[string]interface{}
+
+	// Page is the page of the table, default 1
+	Page int
+
+	// ParentValue is the id of the parent record like the query variable of "parent" in the urls,
+	// the table is filtered by parent.localConnectionField and the created records are linked to the parent
+	ParentValue string
+
+	HideBreadcrumbs bool
+
+	// HideAddForm hides the add form above the table
+	HideAddForm bool
+
+	// HideScripts omits the editing scripts of the table if the page loads them already, e.g. by another fragment
+	HideScripts bool
+}
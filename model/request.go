@@ -0,0 +1,227 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// H is a shortcut for the JSON objects of the responses and the template data
+type H map[string]any
+
+// maxMultipartMemory is the memory limit of the parsed multipart forms, the rest of the files is stored on disk
+const maxMultipartMemory = 32 << 20
+
+// Request is the transport-agnostic request passed to the Service handlers and the WedytaConfig callbacks.
+// Adapters (controller for gin, adapter/httpadapter for net/http, adapter/chiadapter for chi) build it from the HTTP request
+// and write the Response recorded by the handler.
+type Request struct {
+	// Request is the incoming HTTP request
+	Request *http.Request
+
+	// Params are the route parameters, e.g. modelName and recID
+	Params map[string]string
+
+	// Keys are the values of the request set by the application, e.g. the current user, copied to the CommitEvent.
+	// The gin adapter starts with the keys of the gin context.
+	Keys map[string]any
+
+	// RemoteIP is the client IP resolved by the adapter, e.g. by the trusted proxies of gin, default: the host of Request.RemoteAddr
+	RemoteIP string
+
+	// Response is the result of the handler written by the adapter
+	Response Response
+}
+
+// Response is the result of a Service handler
+type Response struct {
+	Status      int
+	Header      http.Header
+	ContentType string
+	Body        []byte
+
+	// Template is set instead of Body if the page is rendered by the template of the host application, see WedytaConfig.Template.
+	// Only the gin adapter renders it.
+	Template     string
+	TemplateData any
+}
+
+// NewRequest wraps the HTTP request with its route parameters
+func NewRequest(r *http.Request, params map[string]string) *Request {
+	if params == nil {
+		params = make(map[string]string)
+	}
+	return &Request{
+		Request: r,
+		Params:  params,
+	}
+}
+
+// Param returns the route parameter
+func (r *Request) Param(name string) string {
+	return r.Params[name]
+}
+
+// Query returns the URL query value
+func (r *Request) Query(name string) string {
+	value, _ := r.GetQuery(name)
+	return value
+}
+
+// GetQuery returns the URL query value and whether it is present
+func (r *Request) GetQuery(name string) (string, bool) {
+	values, found := r.Request.URL.Query()[name]
+	if !found || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// GetHeader returns the request header
+func (r *Request) GetHeader(name string) string {
+	return r.Request.Header.Get(name)
+}
+
+// PostForm returns the value of the urlencoded or multipart form
+func (r *Request) PostForm(name string) string {
+	if r.Request.PostForm == nil {
+		_ = r.Request.ParseMultipartForm(maxMultipartMemory)
+	}
+	return r.Request.PostFormValue(name)
+}
+
+// FormFile returns the first file of the multipart form
+func (r *Request) FormFile(name string) (*multipart.FileHeader, error) {
+	if r.Request.MultipartForm == nil {
+		if err := r.Request.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return nil, err
+		}
+	}
+	files := r.Request.MultipartForm.File[name]
+	if len(files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+// SaveUploadedFile writes the file of the multipart form to the path
+func (r *Request) SaveUploadedFile(file *multipart.FileHeader, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+// ClientIP returns RemoteIP or the host of the remote address
+func (r *Request) ClientIP() string {
+	if r.RemoteIP != "" {
+		return r.RemoteIP
+	}
+	host, _, err := net.SplitHostPort(r.Request.RemoteAddr)
+	if err != nil {
+		return r.Request.RemoteAddr
+	}
+	return host
+}
+
+// ShouldBindJSON decodes the JSON body into obj
+func (r *Request) ShouldBindJSON(obj any) error {
+	if r.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+	return json.NewDecoder(r.Request.Body).Decode(obj)
+}
+
+// Set stores the value in Keys
+func (r *Request) Set(key string, value any) {
+	if r.Keys == nil {
+		r.Keys = make(map[string]any)
+	}
+	r.Keys[key] = value
+}
+
+// Get returns the value of Keys
+func (r *Request) Get(key string) (any, bool) {
+	value, found := r.Keys[key]
+	return value, found
+}
+
+// Header sets the response header
+func (r *Request) Header(key, value string) {
+	if r.Response.Header == nil {
+		r.Response.Header = make(http.Header)
+	}
+	r.Response.Header.Set(key, value)
+}
+
+// JSON records the JSON response
+func (r *Request) JSON(status int, obj any) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		r.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	r.Data(status, "application/json; charset=utf-8", body)
+}
+
+// String records the plain text response
+func (r *Request) String(status int, text string) {
+	r.Data(status, "text/plain; charset=utf-8", []byte(text))
+}
+
+// Data records the response with the content type
+func (r *Request) Data(status int, contentType string, data []byte) {
+	r.Response.Status = status
+	r.Response.ContentType = contentType
+	r.Response.Body = data
+	r.Response.Template = ""
+	r.Response.TemplateData = nil
+}
+
+// HTML records the page to be rendered by the template of the host application
+func (r *Request) HTML(status int, name string, data any) {
+	r.Response.Status = status
+	r.Response.Template = name
+	r.Response.TemplateData = data
+	r.Response.ContentType = "text/html; charset=utf-8"
+	r.Response.Body = nil
+}
+
+// WriteTo writes the recorded response, except the Template one, to w
+func (res *Response) WriteTo(w http.ResponseWriter) {
+	for key, values := range res.Header {
+		w.Header()[key] = values
+	}
+	if res.ContentType != "" {
+		w.Header().Set("Content-Type", res.ContentType)
+	}
+	status := res.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if res.Template != "" {
+		http.Error(w, "WeDyTa: Template "+res.Template+" requires the gin adapter", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(res.Body)
+}
@@ -1,7 +1,9 @@
 package model
 
 import (
-	"github.com/gin-gonic/gin"
+	"html/template"
+	"io/fs"
+
 	"gorm.io/gorm"
 )
 
@@ -13,16 +15,59 @@ type WedytaConfig struct {
 	// Default: config/wedyta
 	ConfigDir string
 
+	// ConfigFS is the file system with model configurations, e.g. embed.FS, os.DirFS or fstest.MapFS.
+	// Configurations may be .json, .yaml, .yml or .toml files named after the model.
+	// The files are checked for changes by their mod time, the files without it (embed.FS) are read once until Service.Reload.
+	// Default: os.DirFS(ConfigDir)
+	ConfigFS fs.FS
+
+	// WatchConfigDir reloads the configs changed in ConfigDir in the background instead of checking the files on every request,
+	// the models extending the changed files and their children are reloaded too, see also Service.Reload and Service.Close.
+	// ConfigFS must be os.DirFS(ConfigDir) or empty, NewServiceE fails otherwise.
+	WatchConfigDir bool
+
+	// ConfigValidation is the check of all model configs against the database by NewService and on reload, see Service.Validate:
+	// "" or "off" - no check;
+	// "unavailable" - log the issues, models with issues respond with an error instead of being rendered;
+	// "strict" - NewServiceE returns the issues as an error and NewService panics if any config has issues,
+	// Reload returns them keeping the loaded configs.
+	ConfigValidation string
+
 	// The function must return true if the action on the specified table field is allowed.
 	// It should be noted that in some cases the field may be empty when the access check occurs in the context of the entire table, and not a specific field.
 	// It is recommended to place the function in such a way that it has access to the existing functions for checking authorization by the cookie of the main application, and this is the reason why the context is also passed to it.
-	AccessCheckFunc func(context *gin.Context, modelName, fieldName, action string) bool
+	// If the function is not set, the "permissions" matrix of the model config is evaluated against the roles returned by RoleResolver,
+	// models without "permissions" are accessible to everyone.
+	AccessCheckFunc func(context *Request, modelName, fieldName, action string) bool
 
-	// Gin template in which the content generated by the wedyta module will be placed
+	// RoleResolver returns the roles of the current user, used by the default AccessCheckFunc to evaluate the "permissions" matrix.
+	RoleResolver func(context *Request) []string
+
+	// Gin template in which the content generated by the wedyta module will be placed, rendered by the gin adapter only
 	Template string
 
+	// TemplatesFS has *.tmpl files parsed after the default templates of embed/templates, a template defined there replaces the default one of the same name,
+	// e.g. {{define "pagination"}}...{{end}} restyles the pagination of all models, default.tmpl replaces the page layout.
+	// Models may use other templates for their parts by the "templates" config key.
+	TemplatesFS fs.FS
+
+	// TemplateFuncs are added to the functions of the templates: heading, headingOpen, headingClose, t and asset
+	TemplateFuncs template.FuncMap
+
 	// A function that will add a list of variables and their values ​​that should be additionally filled in the template, for example, the username and the like.
-	PrepareTemplateVariables func(context *gin.Context, modelName string, h gin.H)
+	PrepareTemplateVariables func(context *Request, modelName string, h H)
+
+	// DefaultLocale is the locale of the UI when the locale of the user is not supported, default 'en'
+	DefaultLocale string
+
+	// LocaleResolver returns the locale of the current user as a BCP 47 tag, e.g. "ru" or "pt-BR", taken from a cookie or the user profile.
+	// It is matched against DefaultLocale and the locales of the messages. Default: the Accept-Language header.
+	LocaleResolver func(context *Request) string
+
+	// Messages are the translations of the UI texts mapped by locale and the English text, e.g. {"de": {"Add New Record": "Neuer Datensatz"}},
+	// added to the embedded ones of embed/locales or replacing them. Templates translate the texts by {{t "text"}}, scripts by wedytaT("text").
+	// Texts of the model configs are translated by their "locales" key.
+	Messages map[string]map[string]string
 
 	// HeadersTag default 'h2'
 	HeadersTag string
@@ -30,6 +75,9 @@ type WedytaConfig struct {
 	// PaginationRecordsPerPage default 100
 	PaginationRecordsPerPage int
 
+	// IndexPageTitle is the title of the index page /wedyta/ listing the models, default 'Models'
+	IndexPageTitle string
+
 	// BreadcrumbsRootName default 'Home'
 	BreadcrumbsRootName string
 
@@ -46,33 +94,71 @@ type WedytaConfig struct {
 	FileUploadRelativePath string
 
 	// VariableResolver the function to which the variable name will be passed to get the value
-	VariableResolver func(context *gin.Context, modelName string, variableName string) string
-
-	BeforeCreate func(context *gin.Context, db *gorm.DB, table string, insertData map[string]interface{}) (bool, string)
-	BeforeUpdate func(context *gin.Context, db *gorm.DB, table string, id int64, field string)
-	BeforeDelete func(context *gin.Context, db *gorm.DB, table string, id int64)
-	AfterCreate  func(context *gin.Context, db *gorm.DB, table string, id int64)
-	AfterUpdate  func(context *gin.Context, db *gorm.DB, table string, id int64, field string, valueBeforeUpdate string, valueAfterUpdate string)
-	AfterDelete  func(context *gin.Context, db *gorm.DB, table string, id int64)
+	VariableResolver func(context *Request, modelName string, variableName string) string
+
+	// AuditEnabled turns on recording of created, updated and deleted records into the wedyta_audit table,
+	// the table is created by GORM AutoMigrate, the history of a record is shown on the record page.
+	AuditEnabled bool
+
+	// UserResolver returns the name of the current user for the audit trail
+	UserResolver func(context *Request) string
+
+	// Create, update and delete run inside a DB transaction, the db passed to the Before* and After* hooks is the transaction.
+	// An error returned by BeforeUpdate, BeforeDelete or an After* hook aborts the change, rolls the transaction back and is sent to the client.
+	BeforeCreate func(context *Request, db *gorm.DB, table string, insertData map[string]interface{}) (bool, string)
+	BeforeUpdate func(context *Request, db *gorm.DB, table string, id int64, field string) error
+	BeforeDelete func(context *Request, db *gorm.DB, table string, id int64) error
+	AfterCreate  func(context *Request, db *gorm.DB, table string, id int64) error
+	AfterUpdate  func(context *Request, db *gorm.DB, table string, id int64, field string, valueBeforeUpdate string, valueAfterUpdate string) error
+	AfterDelete  func(context *Request, db *gorm.DB, table string, id int64) error
+
+	// AfterCommit is called asynchronously after the transaction of create, update or delete is committed.
+	// It receives a detached copy of the request data instead of the request, which must not be used after the response is written.
+	AfterCommit func(event CommitEvent)
+
+	// BulkActions are the custom actions on the rows selected in the table, mapped by the name listed in "bulkActions" of the model config.
+	// The action is checked by AccessCheckFunc with its name and the empty field.
+	BulkActions map[string]BulkAction
+
+	// RecordActions are the custom actions on a single record, mapped by the name listed in "recordActions" of the model config,
+	// shown as buttons in the stdRecordControls column and executed by POST /wedyta/action.
+	// NewServiceE registers them by Service.RegisterRecordAction before the configs are validated.
+	RecordActions map[string]RecordAction
+
+	// ColumnFuncs are the functions rendering the virtual fields, mapped by the name of "columnDataFunc" of the model config,
+	// e.g. "columnDataFunc": {"photo": {"name": "avatar", "params": {"size": 48}}}. The names stdRecordControls and dynamicColumnDataFunc are built-in.
+	// NewServiceE registers them by Service.RegisterColumnFunc before the configs are validated.
+	ColumnFuncs map[string]ColumnFunc
 
 	// DynamicColumnDataFunc allows dynamic generation of additional table cells
 	// by invoking a user-defined function. This enables adding custom columns to
 	// each row based on record data, user context, or other dynamic logic.
-	DynamicColumnDataFunc func(context *gin.Context, db *gorm.DB, table string, field string, record map[string]interface{}) string
+	// ColumnFuncs are the named functions with params instead of the single one.
+	DynamicColumnDataFunc func(context *Request, db *gorm.DB, table string, field string, record map[string]interface{}) string
 
 	// EncryptPlainPasswordFunc allows custom encryption of plain text passwords
 	// before storing them in the database. This function takes a raw password string
 	// and returns its encrypted form, enabling integration with different hashing
 	// or encryption strategies such as bcrypt, scrypt, or custom algorithms.
-	EncryptPlainPasswordFunc func(context *gin.Context, table string, field string, record map[string]interface{}, plainPassword string) (string, error)
+	EncryptPlainPasswordFunc func(context *Request, table string, field string, record map[string]interface{}, plainPassword string) (string, error)
 
-	// JQueryScriptTag default: <script src="https://code.jquery.com/jquery-3.7.1.min.js"></script>
+	// AssetsSource is where the pages load Bootstrap, Bootstrap Icons, jQuery and Summernote from:
+	// "embedded" (default) - the copies vendored into embed/static/vendor by go generate ./embed, served under /wedyta/static/vendor;
+	// "cdn" - jsDelivr, code.jquery.com and cdnjs.
+	// NewServiceE fails in the embedded source if any of the files is not vendored.
+	AssetsSource string
+
+	// JQueryScriptTag default: the script tag of jQuery 3.7.1 of AssetsSource
 	JQueryScriptTag string
 
-	// SummernoteInitTags default:
-	// <link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.css">
-	// <script src="https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.js"></script>
+	// SummernoteInitTags default: the stylesheet and script tags of Summernote 0.9.1 of AssetsSource
 	SummernoteInitTags string
 
 	SummernoteDefaultParams string
 }
+
+// AssetsSource values of WedytaConfig.AssetsSource
+const (
+	AssetsSourceEmbedded = "embedded"
+	AssetsSourceCDN      = "cdn"
+)
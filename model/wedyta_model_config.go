@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
+
+	"golang.org/x/text/language"
 )
 
 type ConfigOfModel struct {
-	ModelName           string
+	SchemaURL           string                            `json:"$schema,omitempty"`
+	Extends             StringList                        `json:"extends"`
+	ModelName           string                            `json:"-"`
 	PageTitle           string                            `json:"pageTitle"`
+	Group               string                            `json:"group"`
 	DbTable             string                            `json:"dbTable"`
 	SqlWhereOriginal    string                            `json:"sqlWhere"`
 	Fields              []string                          `json:"fields"`
@@ -23,28 +27,89 @@ type ConfigOfModel struct {
 	AddableFields       []string                          `json:"addableFields"`
 	RequiredFields      []string                          `json:"requiredFields"`
 	EditableFields      []string                          `json:"editableFields"`
-	FieldEditor         map[string]map[string]interface{} `json:"fieldsEditor"`
+	Deletable           bool                              `json:"deletable"`
+	VersionField        string                            `json:"versionField"`
+	SoftDelete          SoftDeleteConfig                  `json:"softDelete"`
+	Duplicate           DuplicateConfig                   `json:"duplicate"`
+	BulkActions         StringList                        `json:"bulkActions"`
+	RecordActions       StringList                        `json:"recordActions"`
+	FieldEditor         map[string]FieldEditorConfig      `json:"fieldsEditor"`
 	NoZeroValueFields   []string                          `json:"noZeroValueFields"`
 	Password            map[string]map[string]string      `json:"password"`
-	ColumnDataFunc      map[string]string                 `json:"columnDataFunc"`
+	ColumnDataFunc      map[string]ColumnDataFuncConfig   `json:"columnDataFunc"`
 	CountRelatedData    map[string]CountRelatedDataConfig `json:"countRelatedData"`
 	Links               map[string]LinkConfig             `json:"links"`
 	Parent              ParentConfig                      `json:"parent"`
-	Breadcrumb          BreadcrumbConfig
-	HasParent           bool
-	DbTablePrimaryKey   string
-	ParentConfig        *ConfigOfModel
-	FieldConfig         map[string]FieldParams
-	HeaderTags          string
-	AdditionalScripts   string
-	SqlWhere            string
-	AdditionalUrlParams string
+	Children            StringList                        `json:"children"`
+	Permissions         map[string]PermissionConfig       `json:"permissions"`
+	Breadcrumb          BreadcrumbConfig                  `json:"breadcrumb"`
+	Templates           map[string]string                 `json:"templates"`
+	Locales             map[string]LocaleConfig           `json:"locales"`
+	FieldDefinitions    map[string]FieldDefinition        `json:"fieldDefinitions"`
+	FieldPresets        map[string]string                 `json:"fieldPresets"`
+	HasParent           bool                              `json:"-"`
+	DbTablePrimaryKey   string                            `json:"-"`
+	ParentConfig        *ConfigOfModel                    `json:"-"`
+	FieldConfig         map[string]FieldParams            `json:"-"`
+	HeaderTags          string                            `json:"-"`
+	AdditionalScripts   string                            `json:"-"`
+	SqlWhere            string                            `json:"-"`
+	AdditionalUrlParams string                            `json:"-"`
+	Locale              language.Tag                      `json:"-"` // locale of the request, set on the copy returned by prepareModelConfig
 	//InsertModeHiddenFields []string
 }
 
+// LocaleConfig replaces the texts of the model config in a locale: "locales": {"ru": {"pageTitle": "Товары", "headers": {"name": "Название"}}}
+type LocaleConfig struct {
+	PageTitle string            `json:"pageTitle"`
+	Group     string            `json:"group"`
+	Headers   map[string]string `json:"headers"`
+	Titles    map[string]string `json:"titles"`
+}
+
+// FieldEditorConfig is the editor of a field: {"type": "summernote", ...editor options}
+type FieldEditorConfig map[string]interface{}
+
 type CachedModelConfig struct {
-	Config  *ConfigOfModel
-	ModTime time.Time
+	Config *ConfigOfModel
+	// FileVersions of the config file and the files it extends, mapped by name:
+	// mod time or "immutable" if the file system provides no mod time, such files aren't checked. Empty for registered structs.
+	FileVersions map[string]string
+}
+
+// FieldDefinition is a reusable set of field settings declared in "fieldDefinitions", usually of a shared config
+// included by "extends", and applied to the fields by "fieldPresets": {"created_at": "timestamp"}.
+// Settings of the model itself take precedence over the definition.
+type FieldDefinition struct {
+	Header         string            `json:"header"`
+	Title          string            `json:"title"`
+	Class          string            `json:"class"`
+	DisplayMode    string            `json:"displayMode"`
+	DateTimeFormat string            `json:"dateTimeFormat"`
+	Editor         FieldEditorConfig `json:"editor"`
+	RelatedData    *RelatedDataEntry `json:"relatedData"`
+	Password       bool              `json:"password"`
+	Editable       bool              `json:"editable"`
+	Addable        bool              `json:"addable"`
+	Required       bool              `json:"required"`
+}
+
+// StringList is a list of strings which may be written as a single string
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected string or list of strings: %s", string(data))
+	}
+	*l = list
+	return nil
 }
 
 type CountRelatedDataConfig struct {
@@ -79,17 +144,28 @@ type FieldParams struct {
 
 type RenderTableCache struct {
 	RelatedData map[string]string
+	Permissions map[string]bool
+	ReadOnly    bool // disables editing controls, e.g. in the trash view
+}
+
+// PermissionConfig describes what a role is allowed to do with a model.
+// Actions lists the permitted actions: read, create, update, delete, restore, purge or "*" for all of them.
+// Fields optionally narrows an action to a list of fields, e.g. {"update": ["title", "body"]},
+// actions without an entry in Fields are permitted for every field.
+type PermissionConfig struct {
+	Actions []string            `json:"actions"`
+	Fields  map[string][]string `json:"fields"`
 }
 
 type BreadcrumbConfig struct {
-	LabelField string
+	LabelField string `json:"labelField"`
 }
 
 type ParentConfig struct {
-	ModelName            string
-	LocalConnectionField string
-	QueryVariableName    string
-	QueryVariableValue   string
+	ModelName            string `json:"modelName"`
+	LocalConnectionField string `json:"localConnectionField"`
+	QueryVariableName    string `json:"queryVariableName"`
+	QueryVariableValue   string `json:"-"`
 }
 
 type RelatedDataEntry struct {
@@ -131,3 +207,87 @@ func (r *RelatedDataEntry) UnmarshalJSON(data []byte) error {
 	*r = RelatedDataEntry(tmp)
 	return nil
 }
+
+// DuplicateConfig adds the duplicate action to stdRecordControls, opening the create form prefilled from the record
+type DuplicateConfig struct {
+	Enabled bool
+	// ResetFields are left empty in the form besides the primary key, the unique columns and the passwords
+	ResetFields []string
+}
+
+func (c *DuplicateConfig) UnmarshalJSON(data []byte) error {
+	// "duplicate": true
+	if err := json.Unmarshal(data, &c.Enabled); err == nil {
+		c.ResetFields = nil
+		return nil
+	}
+
+	// "duplicate": {"resetFields": ["sku"]}
+	var settings struct {
+		ResetFields []string `json:"resetFields"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("invalid duplicate value, expected true or {\"resetFields\": [...]}: %s", string(data))
+	}
+	c.Enabled = true
+	c.ResetFields = settings.ResetFields
+	return nil
+}
+
+const SoftDeleteDefaultField = "deleted_at"
+
+// ColumnDataFuncConfig is the function rendering the virtual field: stdRecordControls, dynamicColumnDataFunc
+// or the name of a registered function, see WedytaConfig.ColumnFuncs
+type ColumnDataFuncConfig struct {
+	Name   string
+	Params ColumnParams // passed to the registered function
+}
+
+func (c *ColumnDataFuncConfig) UnmarshalJSON(data []byte) error {
+	// "columnDataFunc": {"controls": "stdRecordControls"}
+	if err := json.Unmarshal(data, &c.Name); err == nil {
+		c.Params = nil
+		return nil
+	}
+
+	// "columnDataFunc": {"photo": {"name": "avatar", "params": {"size": 48}}}
+	var settings struct {
+		Name   string       `json:"name"`
+		Params ColumnParams `json:"params"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil || settings.Name == "" {
+		return fmt.Errorf("invalid columnDataFunc value, expected a function name or {\"name\": ..., \"params\": {...}}: %s", string(data))
+	}
+	c.Name = settings.Name
+	c.Params = settings.Params
+	return nil
+}
+
+// SoftDeleteConfig makes delete set the column to the current time instead of removing the row
+type SoftDeleteConfig struct {
+	Field string
+}
+
+func (c SoftDeleteConfig) Enabled() bool {
+	return c.Field != ""
+}
+
+func (c *SoftDeleteConfig) UnmarshalJSON(data []byte) error {
+	// "softDelete": true - uses deleted_at column
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		c.Field = ""
+		if enabled {
+			c.Field = SoftDeleteDefaultField
+		}
+		return nil
+	}
+
+	// "softDelete": "removed_at" - custom column
+	var field string
+	if err := json.Unmarshal(data, &field); err != nil {
+		return fmt.Errorf("invalid softDelete value, expected true or column name: %s", string(data))
+	}
+	c.Field = strings.TrimSpace(field)
+	return nil
+}
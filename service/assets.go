@@ -0,0 +1,74 @@
+package service
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/pa-pe/wedyta/embed"
+	"github.com/pa-pe/wedyta/model"
+)
+
+// assetRef is the URL of a frontend dependency with its subresource integrity
+type assetRef struct {
+	URL       string
+	Integrity string
+}
+
+// resolveAssets maps the assets by name to their embedded copies, or to the CDN if the source is "cdn".
+// The embedded source fails if any of the files, including the fonts of the stylesheets, is not vendored.
+func resolveAssets(source string) (map[string]assetRef, error) {
+	assets := make(map[string]assetRef)
+	var missing []string
+	for _, asset := range embed.Assets {
+		if source == model.AssetsSourceCDN {
+			if asset.Name != "" {
+				assets[asset.Name] = assetRef{URL: asset.CDN, Integrity: asset.Integrity}
+			}
+			continue
+		}
+
+		data, err := fs.ReadFile(embed.EmbeddedFiles, asset.Path)
+		if err != nil {
+			missing = append(missing, asset.Path)
+			continue
+		}
+		if asset.Name != "" {
+			sum := sha512.Sum384(data)
+			assets[asset.Name] = assetRef{
+				URL:       "/wedyta/" + asset.Path,
+				Integrity: "sha384-" + base64.StdEncoding.EncodeToString(sum[:]),
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("WeDyTa: the embedded assets are not vendored: %s, run go generate ./embed or set AssetsSource to %q",
+			strings.Join(missing, ", "), model.AssetsSourceCDN)
+	}
+
+	return assets, nil
+}
+
+// assetTag renders the stylesheet or script tag of the asset
+func assetTag(assets map[string]assetRef, name string) template.HTML {
+	asset, found := assets[name]
+	if !found {
+		return template.HTML("<!-- unknown asset " + html.EscapeString(name) + " -->")
+	}
+
+	attributes := ""
+	if asset.Integrity != "" {
+		attributes = ` integrity="` + asset.Integrity + `" crossorigin="anonymous"`
+	}
+
+	if path.Ext(name) == ".css" {
+		return template.HTML(`<link rel="stylesheet" href="` + html.EscapeString(asset.URL) + `"` + attributes + `>`)
+	}
+	return template.HTML(`<script src="` + html.EscapeString(asset.URL) + `"` + attributes + `></script>`)
+}
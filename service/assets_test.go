@@ -0,0 +1,70 @@
+package service
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/pa-pe/wedyta/embed"
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestAssetTag(t *testing.T) {
+	assets := map[string]assetRef{
+		"bootstrap.css": {URL: "/wedyta/static/vendor/bootstrap/5.3.7/bootstrap.min.css", Integrity: "sha384-abc"},
+		"jquery.js":     {URL: "https://code.jquery.com/jquery-3.7.1.min.js"},
+	}
+
+	tests := map[string]string{
+		"bootstrap.css": `<link rel="stylesheet" href="/wedyta/static/vendor/bootstrap/5.3.7/bootstrap.min.css" integrity="sha384-abc" crossorigin="anonymous">`,
+		"jquery.js":     `<script src="https://code.jquery.com/jquery-3.7.1.min.js"></script>`,
+		"missing.js":    `<!-- unknown asset missing.js -->`,
+	}
+	for name, expected := range tests {
+		if tag := string(assetTag(assets, name)); tag != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, tag)
+		}
+	}
+}
+
+func TestResolveAssets_CDN(t *testing.T) {
+	assets, err := resolveAssets(model.AssetsSourceCDN)
+	if err != nil {
+		t.Fatalf("resolveAssets failed: %v", err)
+	}
+	for _, name := range []string{"bootstrap.css", "bootstrap.js", "bootstrap-icons.css", "jquery.js", "summernote.css", "summernote.js"} {
+		if !strings.HasPrefix(assets[name].URL, "https://") {
+			t.Errorf("%s: expected CDN URL, got %q", name, assets[name].URL)
+		}
+	}
+	if assets["bootstrap.js"].Integrity == "" {
+		t.Errorf("expected the pinned integrity of the CDN file")
+	}
+}
+
+func TestResolveAssets_Embedded(t *testing.T) {
+	var missing []string
+	for _, asset := range embed.Assets {
+		if _, err := fs.Stat(embed.EmbeddedFiles, asset.Path); err != nil {
+			missing = append(missing, asset.Path)
+		}
+	}
+
+	assets, err := resolveAssets(model.AssetsSourceEmbedded)
+	if len(missing) > 0 {
+		// the embedded source doesn't fall back to the CDN
+		if err == nil || !strings.Contains(err.Error(), missing[0]) {
+			t.Errorf("expected the error of the missing %s, got %v", missing[0], err)
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("resolveAssets failed: %v", err)
+	}
+	for _, name := range []string{"bootstrap.css", "bootstrap.js", "bootstrap-icons.css", "jquery.js", "summernote.css", "summernote.js"} {
+		if !strings.HasPrefix(assets[name].URL, "/wedyta/static/vendor/") || !strings.HasPrefix(assets[name].Integrity, "sha384-") {
+			t.Errorf("%s: expected the embedded copy with integrity, got %+v", name, assets[name])
+		}
+	}
+}
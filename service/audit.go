@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+const auditMaskedValue = "********"
+
+// writeAudit stores the record changes to the wedyta_audit table if the audit is enabled
+func (s *Service) writeAudit(ctx *model.Request, db *gorm.DB, mConfig *model.ConfigOfModel, recordID int64, action string, changes []model.FieldChange) error {
+	if !s.Config.AuditEnabled {
+		return nil
+	}
+
+	user := ""
+	if s.Config.UserResolver != nil {
+		user = s.Config.UserResolver(ctx)
+	}
+
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	auditRecord := model.AuditRecord{
+		CreatedAt: time.Now(),
+		User:      user,
+		Model:     mConfig.ModelName,
+		DbTable:   mConfig.DbTable,
+		RecordID:  recordID,
+		Action:    action,
+		Changes:   string(changesJson),
+	}
+
+	return db.Create(&auditRecord).Error
+}
+
+// auditChanges builds the sorted list of field changes, password values are masked
+func auditChanges(mConfig *model.ConfigOfModel, before, after map[string]interface{}) []model.FieldChange {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	var changes []model.FieldChange
+	for field := range fields {
+		change := model.FieldChange{Field: field}
+		if value, exists := before[field]; exists && value != nil {
+			change.Before = fmt.Sprint(value)
+		}
+		if value, exists := after[field]; exists && value != nil {
+			change.After = fmt.Sprint(value)
+		}
+
+		if change.Before == change.After {
+			continue
+		}
+
+		if mConfig.FieldConfig[field].IsPassword {
+			if change.Before != "" {
+				change.Before = auditMaskedValue
+			}
+			if change.After != "" {
+				change.After = auditMaskedValue
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+func (s *Service) loadAuditHistory(mConfig *model.ConfigOfModel, recordID int64) ([]model.AuditRecord, error) {
+	var history []model.AuditRecord
+	err := s.DB.
+		Where("model = ? AND record_id = ?", mConfig.ModelName, recordID).
+		Order("created_at DESC, id DESC").
+		Find(&history).Error
+
+	return history, err
+}
+
+// renderAuditHistory renders the diff timeline of the record, the changes of fields the user can't read are skipped
+func (s *Service) renderAuditHistory(ctx *model.Request, mConfig *model.ConfigOfModel, recordID int64, cache *model.RenderTableCache) (template.HTML, error) {
+	var data historyData
+
+	history, err := s.loadAuditHistory(mConfig, recordID)
+	if err != nil {
+		log.Printf("WeDyTa: can't load audit history of %s #%d: %v", mConfig.ModelName, recordID, err)
+		data.Error = true
+	}
+
+	for _, auditRecord := range history {
+		var changes []model.FieldChange
+		if err := json.Unmarshal([]byte(auditRecord.Changes), &changes); err != nil {
+			log.Printf("WeDyTa: can't parse audit changes id=%d: %v", auditRecord.ID, err)
+		}
+
+		historyRecord := historyRecord{
+			Date:   auditRecord.CreatedAt.Format("2006-01-02 15:04:05"),
+			User:   auditRecord.User,
+			Action: auditRecord.Action,
+		}
+		for _, change := range changes {
+			if !s.fieldPermitted(ctx, mConfig, change.Field, "read", cache) {
+				continue
+			}
+			header := change.Field
+			if fldCfg, exists := mConfig.FieldConfig[change.Field]; exists && fldCfg.Header != "" {
+				header = fldCfg.Header
+			}
+			historyRecord.Changes = append(historyRecord.Changes, historyChange{Header: header, Before: change.Before, After: change.After})
+		}
+		data.Records = append(data.Records, historyRecord)
+	}
+
+	return s.renderTemplate(mConfig, "history", data)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestAudit(t *testing.T) {
+	fsys := fstest.MapFS{
+		"accounts.json": {Data: []byte(`{"fields": ["id", "name", "secret"], "editableFields": ["name", "secret"],
+			"headers": {"name": "Login", "secret": "Secret key"}, "password": {"secret": {}}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE accounts (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, secret TEXT);
+		INSERT INTO accounts (name, secret) VALUES ('alice', 'old')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := s.DB.AutoMigrate(&model.AuditRecord{}); err != nil {
+		t.Fatalf("failed to migrate the audit table: %v", err)
+	}
+	s.Config.AuditEnabled = true
+	s.Config.UserResolver = func(ctx *model.Request) string { return "admin" }
+	s.Config.EncryptPlainPasswordFunc = func(ctx *model.Request, table, field string, record map[string]interface{}, plainPassword string) (string, error) {
+		return "hashed:" + plainPassword, nil
+	}
+	deniedField := ""
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return fieldName == "" || fieldName != deniedField
+	}
+
+	ctx := model.NewRequest(httptest.NewRequest("POST", "/wedyta/update", strings.NewReader(`{"modelName": "accounts", "id": 1, "name": "bob", "secret": "s3cret"}`)), nil)
+	s.Update(ctx)
+	if ctx.Response.Status != http.StatusOK {
+		t.Fatalf("update failed %d: %s", ctx.Response.Status, ctx.Response.Body)
+	}
+
+	var auditRecords []model.AuditRecord
+	if err := s.DB.Find(&auditRecords).Error; err != nil {
+		t.Fatalf("failed to load the audit: %v", err)
+	}
+	if len(auditRecords) != 1 || auditRecords[0].User != "admin" || auditRecords[0].Action != "update" || auditRecords[0].RecordID != 1 {
+		t.Fatalf("unexpected audit records %+v", auditRecords)
+	}
+	var changes []model.FieldChange
+	if err := json.Unmarshal([]byte(auditRecords[0].Changes), &changes); err != nil {
+		t.Fatalf("can't parse the changes %s: %v", auditRecords[0].Changes, err)
+	}
+	expected := []model.FieldChange{{Field: "name", Before: "alice", After: "bob"}, {Field: "secret", Before: auditMaskedValue, After: auditMaskedValue}}
+	if len(changes) != len(expected) || changes[0] != expected[0] || changes[1] != expected[1] {
+		t.Errorf("expected the changes %v, got %v", expected, changes)
+	}
+	if strings.Contains(auditRecords[0].Changes, "s3cret") || strings.Contains(auditRecords[0].Changes, "old") {
+		t.Errorf("the password leaked to the audit: %s", auditRecords[0].Changes)
+	}
+
+	mConfig, err := s.prepareModelConfig(ctx, "accounts", nil)
+	if err != nil {
+		t.Fatalf("prepareModelConfig failed: %v", err)
+	}
+	deniedField = "secret"
+	history, err := s.renderAuditHistory(ctx, mConfig, 1, &model.RenderTableCache{})
+	if err != nil {
+		t.Fatalf("renderAuditHistory failed: %v", err)
+	}
+	if !strings.Contains(string(history), "Login") || !strings.Contains(string(history), "bob") {
+		t.Errorf("expected the readable change in\n%s", history)
+	}
+	if strings.Contains(string(history), "Secret key") {
+		t.Errorf("unexpected change of the unreadable field in\n%s", history)
+	}
+}
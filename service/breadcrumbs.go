@@ -2,64 +2,61 @@ package service
 
 import (
 	"fmt"
-	"github.com/pa-pe/wedyta/model"
+	"html/template"
 	"log"
+
+	"github.com/pa-pe/wedyta/model"
 )
 
-func (s *Service) breadcrumbBuilder(mConfig *model.ConfigOfModel, recID string, action string) string {
-	breadcrumbStr := `<nav style="--bs-breadcrumb-divider: '` + s.Config.BreadcrumbsDivider + `';" aria-label="breadcrumb">` + "\n"
-	breadcrumbStr += `  <ol class="breadcrumb">` + "\n"
-	breadcrumbStr += `    <li class="breadcrumb-item"><a href="` + s.Config.BreadcrumbsRootUrl + `">` + s.Config.BreadcrumbsRootName + `</a></li>` + "\n"
+func (s *Service) breadcrumbBuilder(mConfig *model.ConfigOfModel, recordLabel string, action string) (template.HTML, error) {
+	items := []breadcrumbItem{{Title: s.translate(mConfig, s.Config.BreadcrumbsRootName), URL: s.Config.BreadcrumbsRootUrl}}
 
 	if mConfig.HasParent {
-		breadcrumbStr += s.renderParentBreadcrumb(mConfig)
+		items = append(items, s.parentBreadcrumbItems(mConfig)...)
 	}
 
-	breadcrumbStr += `    <li class="breadcrumb-item active" aria-current="page"><a href="/wedyta/` + mConfig.ModelName + mConfig.AdditionalUrlParams + `">` + mConfig.PageTitle + `</a>`
+	items = append(items, breadcrumbItem{Title: mConfig.PageTitle, URL: "/wedyta/" + mConfig.ModelName + mConfig.AdditionalUrlParams, Current: true})
 
-	if recID != "" {
-		breadcrumbStr += `</li>` + "\n" + `    <li class="breadcrumb-item active" aria-current="page"> #` + recID
+	if recordLabel != "" {
+		items = append(items, breadcrumbItem{Title: recordLabel, Current: true})
 	}
 	switch action {
-	case "read records":
-	case "read record":
 	case "create":
-		breadcrumbStr += `</li>` + "\n" + `    <li class="breadcrumb-item active" aria-current="page"> ` + "create record"
+		items = append(items, breadcrumbItem{Title: s.translate(mConfig, "create record"), Current: true})
 	case "update":
-		breadcrumbStr += `</li>` + "\n" + `    <li class="breadcrumb-item active" aria-current="page"> ` + "update record"
+		items = append(items, breadcrumbItem{Title: s.translate(mConfig, "update record"), Current: true})
+	case "trash":
+		items = append(items, breadcrumbItem{Title: s.translate(mConfig, "trash"), Current: true})
 	}
+	items[len(items)-1].Last = true
 
-	breadcrumbStr += ` &nbsp; <i class="bi-arrow-repeat" style="color: grey; cursor: pointer;" onClick="window.location.href = window.location.pathname + window.location.search + window.location.hash;" title="Refresh page"></i>` + `</li>` + "\n"
-	breadcrumbStr += `  </ol>` + "\n"
-	breadcrumbStr += `</nav>` + "\n"
-
-	return breadcrumbStr
+	return s.renderTemplate(mConfig, "breadcrumbs", breadcrumbsData{Divider: s.Config.BreadcrumbsDivider, Items: items})
 }
 
-func (s *Service) renderParentBreadcrumb(mConfig *model.ConfigOfModel) string {
-	breadcrumbStr := ""
+func (s *Service) parentBreadcrumbItems(mConfig *model.ConfigOfModel) []breadcrumbItem {
+	var items []breadcrumbItem
 
 	parentMC := mConfig.ParentConfig
-	breadcrumbStr += `    <li class="breadcrumb-item"><a href="/wedyta/` + parentMC.ModelName + parentMC.AdditionalUrlParams + `">` + mConfig.ParentConfig.PageTitle + `</a></li>` + "\n"
+	if parentMC.HasParent {
+		items = s.parentBreadcrumbItems(parentMC)
+	}
+
+	items = append(items, breadcrumbItem{Title: parentMC.PageTitle, URL: "/wedyta/" + parentMC.ModelName + parentMC.AdditionalUrlParams})
 	if mConfig.Parent.QueryVariableName != "" && mConfig.Parent.QueryVariableValue != "" {
 		value := ""
-		if mConfig.ParentConfig.Breadcrumb.LabelField != "" {
+		if parentMC.Breadcrumb.LabelField != "" {
 			var err error
-			value, err = s.takeLabelFieldValue(parentMC.DbTable, parentMC.DbTablePrimaryKey, mConfig.Parent.QueryVariableValue, mConfig.ParentConfig.Breadcrumb.LabelField)
+			value, err = s.takeLabelFieldValue(parentMC.DbTable, parentMC.DbTablePrimaryKey, mConfig.Parent.QueryVariableValue, parentMC.Breadcrumb.LabelField)
 			if err != nil {
 				log.Printf("Error taking label field: %s", err.Error())
 			}
 		} else {
 			value = "#" + mConfig.Parent.QueryVariableValue
 		}
-		breadcrumbStr += `    <li class="breadcrumb-item"><a href="/wedyta/` + parentMC.ModelName + `/` + mConfig.Parent.QueryVariableValue + parentMC.AdditionalUrlParams + `">` + value + `</a></li>` + "\n"
+		items = append(items, breadcrumbItem{Title: value, URL: "/wedyta/" + parentMC.ModelName + "/" + mConfig.Parent.QueryVariableValue + parentMC.AdditionalUrlParams})
 	}
 
-	if parentMC.HasParent {
-		breadcrumbStr = s.renderParentBreadcrumb(parentMC) + breadcrumbStr
-	}
-
-	return breadcrumbStr
+	return items
 }
 
 func (s *Service) takeLabelFieldValue(table, pkField, pkValue, labelField string) (string, error) {
@@ -67,10 +64,20 @@ func (s *Service) takeLabelFieldValue(table, pkField, pkValue, labelField string
 	if err := s.DB.
 		Model(&record).
 		Table(table).
-		Where(fmt.Sprintf("%s = %s", pkField, pkValue)).
+		Where(fmt.Sprintf("%s = ?", pkField), pkValue).
 		Take(&record).Error; err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("%s", record[labelField]), nil
+	return model.Record(record).String(labelField), nil
+}
+
+// recordLabel is the breadcrumb of the record: the value of breadcrumb.labelField or #id
+func recordLabel(mConfig *model.ConfigOfModel, recID string, record map[string]interface{}) string {
+	if mConfig.Breadcrumb.LabelField != "" {
+		if label := model.Record(record).String(mConfig.Breadcrumb.LabelField); label != "" {
+			return label
+		}
+	}
+	return "#" + recID
 }
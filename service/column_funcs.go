@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+// builtinColumnDataFuncs are the functions of "columnDataFunc" rendered by WeDyTa, other names are looked up in the registered functions
+var builtinColumnDataFuncs = []string{"stdRecordControls", "dynamicColumnDataFunc"}
+
+// RegisterColumnFunc makes the function available as "columnDataFunc" of the virtual fields,
+// e.g. "columnDataFunc": {"photo": {"name": "avatar", "params": {"size": 48}}}.
+// It fails if the name is built-in or registered already. NewServiceE registers WedytaConfig.ColumnFuncs
+// before the configs are validated, the functions registered later are seen by the validation of Reload.
+func (s *Service) RegisterColumnFunc(name string, fn model.ColumnFunc) error {
+	if fn == nil {
+		return fmt.Errorf("WeDyTa: column function %q is nil", name)
+	}
+	if slices.Contains(builtinColumnDataFuncs, name) {
+		return fmt.Errorf("WeDyTa: column function %q is built-in", name)
+	}
+
+	s.columnFuncsMu.Lock()
+	defer s.columnFuncsMu.Unlock()
+	if _, exists := s.columnFuncs[name]; exists {
+		return fmt.Errorf("WeDyTa: column function %q is registered already", name)
+	}
+	if s.columnFuncs == nil {
+		s.columnFuncs = make(map[string]model.ColumnFunc)
+	}
+	s.columnFuncs[name] = fn
+	return nil
+}
+
+func (s *Service) lookupColumnFunc(name string) (model.ColumnFunc, bool) {
+	s.columnFuncsMu.RLock()
+	defer s.columnFuncsMu.RUnlock()
+	fn, found := s.columnFuncs[name]
+	return fn, found
+}
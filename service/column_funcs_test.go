@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestColumnFuncs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"fields": ["id", "name", "badge", "owner", "missing"], "columnDataFunc": {"badge": {"name": "badge", "params": {"size": 48, "rounded": true}},
+			"owner": "badge", "missing": "notRegistered"}}`)},
+		"yamlItems.yaml": {Data: []byte("dbTable: items\nfields: [id, badge]\ncolumnDataFunc:\n  badge:\n    name: badge\n    params:\n      size: 16\n")},
+		"broken.json":    {Data: []byte(`{"dbTable": "items", "fields": ["id", "badge"], "columnDataFunc": {"badge": {"params": {"size": 1}}}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`INSERT INTO items (name, owner_id) VALUES ('apple', 7)`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	badge := func(column model.Column) template.HTML {
+		ownerID, _ := column.Record.Int64("owner_id")
+		return template.HTML(fmt.Sprintf(`<span data-size="%d" data-rounded="%t" data-field="%s">%s #%d %s</span>`,
+			column.Params.Int("size", 32), column.Params.Bool("rounded", false), column.Field,
+			template.HTMLEscapeString(column.Record.String("name")), ownerID, column.Request.Query("mark")))
+	}
+	if err := s.RegisterColumnFunc("badge", badge); err != nil {
+		t.Fatalf("RegisterColumnFunc failed: %v", err)
+	}
+	for _, name := range []string{"badge", "stdRecordControls"} {
+		if err := s.RegisterColumnFunc(name, badge); err == nil {
+			t.Errorf("%s: expected the error of the registered name", name)
+		}
+	}
+
+	render := func(modelName string) string {
+		ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/"+modelName+"?mark=x", nil), map[string]string{"modelName": modelName})
+		s.RenderTable(ctx)
+		return string(ctx.Response.Body)
+	}
+
+	tests := []struct {
+		modelName string
+		expected  []string
+	}{
+		{"items", []string{`<span data-size="48" data-rounded="true" data-field="badge">apple #7 x</span>`, `<span data-size="32" data-rounded="false" data-field="owner">apple #7 x</span>`,
+			"!Unknown columnDataFunc: notRegistered"}},
+		{"yamlItems", []string{`<span data-size="16" data-rounded="false" data-field="badge">`}},
+	}
+	for _, test := range tests {
+		body := render(test.modelName)
+		for _, expected := range test.expected {
+			if !strings.Contains(body, expected) {
+				t.Errorf("%s: expected %q in\n%s", test.modelName, expected, body)
+			}
+		}
+	}
+
+	issues := s.ValidateModel("items")
+	if len(issues) != 1 || issues[0].Path != "columnDataFunc.missing" || !strings.Contains(issues[0].Message, `"notRegistered"`) {
+		t.Errorf("expected the issue of the unknown columnDataFunc, got:\n%v", issues.Error())
+	}
+
+	issues = s.ValidateModel("broken")
+	if len(issues) == 0 || !strings.Contains(issues.Error(), "columnDataFunc") {
+		t.Errorf("expected the issue of the columnDataFunc without name, got:\n%v", issues.Error())
+	}
+}
+
+func TestColumnFuncs_StrictValidation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"fields": ["id", "badge"], "columnDataFunc": {"badge": {"name": "badge", "params": {"size": 16}}}}`)},
+	}
+	badge := func(column model.Column) template.HTML { return "badge" }
+
+	// the functions are known to the validation of NewServiceE
+	_, err = NewServiceE(db, &model.WedytaConfig{ConfigFS: fsys, AssetsSource: model.AssetsSourceCDN, ConfigValidation: model.ConfigValidationStrict,
+		ColumnFuncs: map[string]model.ColumnFunc{"badge": badge}})
+	if err != nil {
+		t.Errorf("expected the configured column function to be valid, got: %v", err)
+	}
+	_, err = NewServiceE(db, &model.WedytaConfig{ConfigFS: fsys, AssetsSource: model.AssetsSourceCDN, ConfigValidation: model.ConfigValidationStrict,
+		ColumnFuncs: map[string]model.ColumnFunc{"badge": badge, "stdRecordControls": badge}})
+	if err == nil || !strings.Contains(err.Error(), "built-in") {
+		t.Errorf("expected the error of the built-in name, got: %v", err)
+	}
+}
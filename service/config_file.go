@@ -0,0 +1,269 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileExtensions are the supported formats of model configs, in order of precedence
+var configFileExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// fragmentPrefix marks the shared configs included by "extends", they are not models
+const fragmentPrefix = "_"
+
+// isConfigFragment reports whether the config is a fragment of "extends", e.g. _common or sub/_base
+func isConfigFragment(name string) bool {
+	return strings.HasPrefix(path.Base(name), fragmentPrefix)
+}
+
+// findConfigFile returns the name and the info of the model config in ConfigFS
+func (s *Service) findConfigFile(modelName string) (string, fs.FileInfo, error) {
+	for _, ext := range configFileExtensions {
+		name := modelName + ext
+		info, err := fs.Stat(s.Config.ConfigFS, name)
+		if err == nil {
+			return name, info, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", nil, err
+		}
+	}
+
+	return "", nil, fs.ErrNotExist
+}
+
+// immutableConfigVersion is the version of the files without mod time, e.g. of embed.FS,
+// they are read once and cached until Service.Reload
+const immutableConfigVersion = "immutable"
+
+// configFileVersion returns the mod time of the file or immutableConfigVersion if the file system provides no mod time
+func configFileVersion(info fs.FileInfo) string {
+	if modTime := info.ModTime(); !modTime.IsZero() {
+		return modTime.UTC().Format(time.RFC3339Nano)
+	}
+	return immutableConfigVersion
+}
+
+// configFilesChanged reports whether any of the files has a version other than cached
+func (s *Service) configFilesChanged(fileVersions map[string]string) bool {
+	for name, version := range fileVersions {
+		if version == immutableConfigVersion {
+			continue
+		}
+
+		info, err := fs.Stat(s.Config.ConfigFS, name)
+		if err != nil || configFileVersion(info) != version {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeConfigDocument unmarshals the config file to a generic map by the extension of the file name
+func decodeConfigDocument(name string, data []byte) (map[string]interface{}, error) {
+	var document map[string]interface{}
+	var err error
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		err = json.Unmarshal(data, &document)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		document = make(map[string]interface{})
+	}
+	return document, nil
+}
+
+// resolveConfigDocument reads the config with the configs it extends merged under it,
+// the versions of all read files are put into fileVersions
+func (s *Service) resolveConfigDocument(configRef string, fileVersions map[string]string, chain []string) (map[string]interface{}, error) {
+	if slices.Contains(chain, configRef) {
+		return nil, fmt.Errorf("extends cycle: %s -> %s", strings.Join(chain, " -> "), configRef)
+	}
+	chain = append(chain, configRef)
+
+	name, info, err := s.findConfigFile(configRef)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %v", configRef, err)
+	}
+
+	data, err := fs.ReadFile(s.Config.ConfigFS, name)
+	if err != nil {
+		return nil, err
+	}
+	fileVersions[name] = configFileVersion(info)
+
+	document, err := decodeConfigDocument(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	var extends model.StringList
+	if rawExtends, exists := document["extends"]; exists {
+		encoded, _ := json.Marshal(rawExtends)
+		if err := json.Unmarshal(encoded, &extends); err != nil {
+			return nil, fmt.Errorf("%s: extends: %v", name, err)
+		}
+		delete(document, "extends")
+	}
+
+	merged := make(map[string]interface{})
+	for _, baseRef := range extends {
+		// relative to the folder of the including config
+		base, err := s.resolveConfigDocument(path.Join(path.Dir(configRef), baseRef), fileVersions, chain)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeConfigDocuments(merged, base)
+	}
+
+	return mergeConfigDocuments(merged, document), nil
+}
+
+// mergeConfigDocuments merges the objects of the override recursively into the base, other values replace the base ones
+func mergeConfigDocuments(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range override {
+		baseObject, baseIsObject := merged[key].(map[string]interface{})
+		overrideObject, overrideIsObject := value.(map[string]interface{})
+		if baseIsObject && overrideIsObject {
+			merged[key] = mergeConfigDocuments(baseObject, overrideObject)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// unknownFieldPresetsError lists the fields of "fieldPresets" referencing unknown field definitions
+type unknownFieldPresetsError []string
+
+func (e unknownFieldPresetsError) Error() string {
+	return "unknown field definitions of fieldPresets: " + strings.Join(e, ", ")
+}
+
+// readModelConfigFile reads the model config file with the configs it extends and applies the field presets.
+// The versions of the read files are returned even on error, so a fix of any of them is noticed.
+func (s *Service) readModelConfigFile(modelName string) (*model.ConfigOfModel, map[string]string, error) {
+	fileVersions := make(map[string]string)
+	document, err := s.resolveConfigDocument(modelName, fileVersions, nil)
+	if err != nil {
+		return nil, fileVersions, err
+	}
+
+	// JSON is the common form, so the JSON tags and unmarshalers of the model config apply to every format
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fileVersions, err
+	}
+
+	var mConfig model.ConfigOfModel
+	if err := json.Unmarshal(data, &mConfig); err != nil {
+		return nil, fileVersions, err
+	}
+
+	if unknown := applyFieldPresets(&mConfig); len(unknown) > 0 {
+		return nil, fileVersions, unknownFieldPresetsError(unknown)
+	}
+
+	return &mConfig, fileVersions, nil
+}
+
+// readRawModelConfig reads the model config from ConfigFS or the registered struct without defaults and caching
+func (s *Service) readRawModelConfig(modelName string) (*model.ConfigOfModel, error) {
+	if _, _, err := s.findConfigFile(modelName); err != nil {
+		if reg, registered := lookupRegisteredModel(modelName); registered {
+			return s.buildRegisteredModelConfig(reg)
+		}
+		return nil, err
+	}
+
+	mConfig, _, err := s.readModelConfigFile(modelName)
+	return mConfig, err
+}
+
+// modelExists reports whether the model has a config file or is registered
+func (s *Service) modelExists(modelName string) bool {
+	if isConfigFragment(modelName) {
+		return false
+	}
+	if _, registered := lookupRegisteredModel(modelName); registered {
+		return true
+	}
+	_, _, err := s.findConfigFile(modelName)
+	return err == nil
+}
+
+// applyFieldPresets applies "fieldDefinitions" to the fields of "fieldPresets", settings of the model take precedence.
+// Returns the fields referencing unknown definitions.
+func applyFieldPresets(mConfig *model.ConfigOfModel) []string {
+	if len(mConfig.FieldPresets) == 0 {
+		return nil
+	}
+
+	mConfig.Headers = cloneOrMake(mConfig.Headers)
+	mConfig.Titles = cloneOrMake(mConfig.Titles)
+	mConfig.Classes = cloneOrMake(mConfig.Classes)
+	mConfig.DisplayMode = cloneOrMake(mConfig.DisplayMode)
+	mConfig.DateTimeFields = cloneOrMake(mConfig.DateTimeFields)
+	mConfig.FieldEditor = cloneOrMake(mConfig.FieldEditor)
+	mConfig.RelatedData = cloneOrMake(mConfig.RelatedData)
+	mConfig.Password = cloneOrMake(mConfig.Password)
+
+	var unknown []string
+	for _, field := range mapKeys(mConfig.FieldPresets) {
+		definition, exists := mConfig.FieldDefinitions[mConfig.FieldPresets[field]]
+		if !exists {
+			unknown = append(unknown, field)
+			continue
+		}
+
+		setIfAbsent(mConfig.Headers, field, definition.Header)
+		setIfAbsent(mConfig.Titles, field, definition.Title)
+		setIfAbsent(mConfig.Classes, field, definition.Class)
+		setIfAbsent(mConfig.DisplayMode, field, definition.DisplayMode)
+		setIfAbsent(mConfig.DateTimeFields, field, definition.DateTimeFormat)
+
+		if _, exists := mConfig.FieldEditor[field]; !exists && definition.Editor != nil {
+			mConfig.FieldEditor[field] = definition.Editor
+		}
+		if _, exists := mConfig.RelatedData[field]; !exists && definition.RelatedData != nil {
+			mConfig.RelatedData[field] = *definition.RelatedData
+		}
+		if _, exists := mConfig.Password[field]; !exists && definition.Password {
+			mConfig.Password[field] = map[string]string{}
+		}
+
+		if definition.Editable && !slices.Contains(mConfig.EditableFields, field) {
+			mConfig.EditableFields = append(mConfig.EditableFields, field)
+		}
+		if definition.Addable && !slices.Contains(mConfig.AddableFields, field) {
+			mConfig.AddableFields = append(mConfig.AddableFields, field)
+		}
+		if definition.Required && !slices.Contains(mConfig.RequiredFields, field) {
+			mConfig.RequiredFields = append(mConfig.RequiredFields, field)
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newConfigFileTestService(t *testing.T, fsys fstest.MapFS) (*Service, *model.Request) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, owner_id INTEGER);`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	// the assets are vendored by go generate ./embed, the tests don't depend on them
+	s := NewService(db, &model.WedytaConfig{ConfigFS: fsys, AssetsSource: model.AssetsSourceCDN})
+
+	ctx := model.NewRequest(httptest.NewRequest("GET", "/", nil), nil)
+
+	return s, ctx
+}
+
+func TestPrepareModelConfig_Formats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"jsonItems.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"], "headers": {"name": "Name"}}`)},
+		"yamlItems.yaml": {Data: []byte("dbTable: items\nfields: [id, name, owner_id]\nheaders:\n  name: Name\nrelatedData:\n  owner_id: web_users.username\nsoftDelete: true\n")},
+		"ymlItems.yml":   {Data: []byte("dbTable: items\nfields:\n  - id\n  - name\n")},
+		"tomlItems.toml": {Data: []byte("dbTable = \"items\"\nfields = [\"id\", \"name\"]\neditableFields = [\"name\"]\n\n[headers]\nname = \"Name\"\n")},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+
+	for _, modelName := range []string{"jsonItems", "yamlItems", "ymlItems", "tomlItems"} {
+		mConfig, err := s.prepareModelConfig(ctx, modelName, nil)
+		if err != nil {
+			t.Errorf("%s: prepareModelConfig failed: %v", modelName, err)
+			continue
+		}
+		if mConfig.DbTable != "items" || mConfig.DbTablePrimaryKey != "id" || !reflect.DeepEqual(mConfig.Fields[:2], []string{"id", "name"}) {
+			t.Errorf("%s: unexpected config: table=%q pk=%q fields=%v", modelName, mConfig.DbTable, mConfig.DbTablePrimaryKey, mConfig.Fields)
+		}
+	}
+
+	mConfig, _ := s.prepareModelConfig(ctx, "yamlItems", nil)
+	if mConfig.RelatedData["owner_id"].Table != "web_users" || mConfig.SoftDelete.Field != model.SoftDeleteDefaultField {
+		t.Errorf("custom unmarshalers are not applied to YAML: %+v %+v", mConfig.RelatedData, mConfig.SoftDelete)
+	}
+
+	mConfig, _ = s.prepareModelConfig(ctx, "tomlItems", nil)
+	if mConfig.Headers["name"] != "Name" || !mConfig.FieldConfig["name"].IsEditable {
+		t.Errorf("unexpected TOML config: %+v", mConfig.FieldConfig["name"])
+	}
+
+	if _, err := s.prepareModelConfig(ctx, "missing", nil); err == nil {
+		t.Errorf("expected error for missing config")
+	}
+	if _, err := s.prepareModelConfig(ctx, "../jsonItems", nil); err == nil {
+		t.Errorf("expected error for invalid model name")
+	}
+}
+
+func TestPrepareModelConfig_CacheByModTime(t *testing.T) {
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"items.json":   {Data: []byte(`{"pageTitle": "First", "fields": ["id"]}`)},
+		"stamped.json": {Data: []byte(`{"dbTable": "items", "pageTitle": "First", "fields": ["id"]}`), ModTime: modTime},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+
+	first, err := s.cachedModelConfig("items")
+	if err != nil {
+		t.Fatalf("cachedModelConfig failed: %v", err)
+	}
+	stamped, err := s.cachedModelConfig("stamped")
+	if err != nil {
+		t.Fatalf("cachedModelConfig failed: %v", err)
+	}
+
+	// the file without mod time is immutable, it's read again by Reload only
+	fsys["items.json"] = &fstest.MapFile{Data: []byte(`{"pageTitle": "Second", "fields": ["id"]}`)}
+	if cached, err := s.cachedModelConfig("items"); err != nil || cached != first {
+		t.Errorf("expected the cached config of the immutable file, err: %v", err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloaded, _ := s.cachedModelConfig("items"); reloaded.PageTitle != "Second" {
+		t.Errorf("expected the reloaded config, got pageTitle %q", reloaded.PageTitle)
+	}
+
+	fsys["stamped.json"] = &fstest.MapFile{Data: []byte(`{"dbTable": "items", "pageTitle": "Second", "fields": ["id"]}`), ModTime: modTime.Add(time.Second)}
+	changed, err := s.cachedModelConfig("stamped")
+	if err != nil {
+		t.Fatalf("cachedModelConfig failed: %v", err)
+	}
+	if changed == stamped || changed.PageTitle != "Second" {
+		t.Errorf("expected reload of the changed file, got pageTitle %q", changed.PageTitle)
+	}
+}
+
+func TestPrepareModelConfig_Extends(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_common.yaml":   {Data: []byte("dbTable: items\nheaders:\n  id: ID\n  name: Common name\nfieldDefinitions:\n  owner:\n    header: Owner\n    relatedData: web_users.username\n    editable: true\n"), ModTime: time.Unix(1, 0)},
+		"items.yaml":     {Data: []byte("extends: _common\nfields: [id, name, owner_id]\nheaders:\n  name: Name\nfieldPresets:\n  owner_id: owner\n")},
+		"sub/_base.json": {Data: []byte(`{"extends": "../_common", "pageTitle": "Sub"}`)},
+		"sub/items.json": {Data: []byte(`{"extends": ["_base"], "fields": ["id"]}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+
+	mConfig, err := s.prepareModelConfig(ctx, "items", nil)
+	if err != nil {
+		t.Fatalf("prepareModelConfig failed: %v", err)
+	}
+	if mConfig.DbTable != "items" || mConfig.Headers["id"] != "ID" || mConfig.Headers["name"] != "Name" {
+		t.Errorf("base config is not merged: table=%q headers=%v", mConfig.DbTable, mConfig.Headers)
+	}
+	if mConfig.Headers["owner_id"] != "Owner" || mConfig.RelatedData["owner_id"].Table != "web_users" || !mConfig.FieldConfig["owner_id"].IsEditable {
+		t.Errorf("field preset is not applied: headers=%v relatedData=%v", mConfig.Headers, mConfig.RelatedData)
+	}
+
+	for _, fragment := range []string{"_common", "sub/_base"} {
+		if _, err := s.prepareModelConfig(ctx, fragment, nil); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected the fragment %s not to be a model, got %v", fragment, err)
+		}
+	}
+	page := model.NewRequest(httptest.NewRequest("GET", "/wedyta/_common", nil), map[string]string{"modelName": "_common"})
+	s.RenderTable(page)
+	if page.Response.Status == http.StatusOK {
+		t.Errorf("expected the fragment not to be rendered, got %s", page.Response.Body)
+	}
+
+	nested, _, err := s.readModelConfigFile("sub/items")
+	if err != nil {
+		t.Fatalf("readModelConfigFile failed: %v", err)
+	}
+	if nested.PageTitle != "Sub" || nested.DbTable != "items" || !reflect.DeepEqual(nested.Fields, []string{"id"}) {
+		t.Errorf("unexpected nested config: %+v", nested)
+	}
+
+	// a change of the included file invalidates the cache
+	fsys["_common.yaml"] = &fstest.MapFile{Data: []byte("dbTable: items\nheaders:\n  id: Key\nfieldDefinitions:\n  owner:\n    header: Owner\n"), ModTime: time.Unix(2, 0)}
+	changed, err := s.prepareModelConfig(ctx, "items", nil)
+	if err != nil {
+		t.Fatalf("prepareModelConfig failed: %v", err)
+	}
+	if changed == mConfig || changed.Headers["id"] != "Key" {
+		t.Errorf("expected reload on change of the base config, got headers %v", changed.Headers)
+	}
+}
+
+func TestPrepareModelConfig_ExtendsErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_a.json":      {Data: []byte(`{"extends": "_b"}`)},
+		"_b.json":      {Data: []byte(`{"extends": "_a"}`)},
+		"cycle.json":   {Data: []byte(`{"extends": "_a", "dbTable": "items"}`)},
+		"missing.json": {Data: []byte(`{"extends": "_none", "dbTable": "items"}`)},
+		"preset.json":  {Data: []byte(`{"dbTable": "items", "fields": ["id"], "fieldPresets": {"id": "none"}}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+
+	for _, modelName := range []string{"cycle", "missing", "preset"} {
+		if _, err := s.prepareModelConfig(ctx, modelName, nil); err == nil {
+			t.Errorf("%s: expected error", modelName)
+		}
+	}
+
+	issues := s.ValidateModel("preset")
+	if len(issues) != 1 || issues[0].Path != "fieldPresets.id" {
+		t.Errorf("expected unknown preset issue, got %v", issues)
+	}
+}
+
+func TestPrepareModelConfig_ParentCycle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.json": {Data: []byte(`{"dbTable": "items", "fields": ["id"], "parent": {"modelName": "b"}}`)},
+		"b.json": {Data: []byte(`{"dbTable": "items", "fields": ["id"], "parent": {"modelName": "a"}}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+
+	if _, err := s.prepareModelConfig(ctx, "a", nil); err == nil || !strings.Contains(err.Error(), "Parent cycle: a -> b -> a") {
+		t.Errorf("expected the error of the parent cycle, got %v", err)
+	}
+}
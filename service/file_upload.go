@@ -3,7 +3,6 @@ package service
 import (
 	"errors"
 	"fmt"
-	"github.com/gin-gonic/gin"
 	"github.com/pa-pe/wedyta/model"
 	"mime/multipart"
 	"net/http"
@@ -13,7 +12,43 @@ import (
 	"strings"
 )
 
-func (s *Service) CheckUploadPermission(ctx *gin.Context, req model.UploadCheckRequest) (model.UploadCheckResponse, error) {
+// HandleUploadCheck answers whether the image may be uploaded to the field of the record
+func (s *Service) HandleUploadCheck(ctx *model.Request) {
+	var req model.UploadCheckRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.UploadCheckResponse{
+			Allowed: false,
+			Message: "Invalid input.",
+		})
+		return
+	}
+
+	res, err := s.CheckUploadPermission(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.UploadCheckResponse{
+			Allowed: false,
+			Message: "Server error: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// HandleImageUpload saves the uploaded image and responds with its URL
+func (s *Service) HandleImageUpload(ctx *model.Request) {
+	imageURL, err := s.ProcessImageUpload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.String(http.StatusOK, imageURL)
+}
+
+func (s *Service) CheckUploadPermission(ctx *model.Request, req model.UploadCheckRequest) (model.UploadCheckResponse, error) {
 	if !s.UploadsConfigured {
 		return model.UploadCheckResponse{
 			Allowed: false,
@@ -57,7 +92,7 @@ func (s *Service) CheckUploadPermission(ctx *gin.Context, req model.UploadCheckR
 	//}, nil
 }
 
-func (s *Service) ProcessImageUpload(ctx *gin.Context) (string, error) {
+func (s *Service) ProcessImageUpload(ctx *model.Request) (string, error) {
 	if !s.UploadsConfigured {
 		return "", errors.New("wedyta uploads not configured")
 	}
@@ -173,6 +208,6 @@ func isAllowedImageMimeType(fileHeader *multipart.FileHeader) (bool, error) {
 	return false, nil
 }
 
-func saveUploadedFile(c *gin.Context, file *multipart.FileHeader, path string) error {
+func saveUploadedFile(c *model.Request, file *multipart.FileHeader, path string) error {
 	return c.SaveUploadedFile(file, path)
 }
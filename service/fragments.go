@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+// ErrAccessDenied is returned by the fragment renderers if WedytaConfig.AccessCheckFunc doesn't permit the action on the model
+var ErrAccessDenied = errors.New("access denied")
+
+// RenderTableFragment renders the table of the model for embedding into a page of the host application,
+// ctx is the request of the page, e.g. model.NewRequest(r, nil), it's passed to the callbacks of WedytaConfig.
+// The page must load Bootstrap, see the asset tags of embed/templates/default.tmpl, the pagination links lead to the /wedyta/<model> route.
+func (s *Service) RenderTableFragment(ctx *model.Request, modelName string, opts model.RenderOptions) (template.HTML, error) {
+	ctx, mConfig, opts, err := s.prepareFragment(ctx, modelName, "read", opts)
+	if err != nil {
+		return "", err
+	}
+
+	html, err := s.renderModelTable(ctx, s.DB, mConfig, opts)
+	return template.HTML(html), err
+}
+
+// RenderRecordFragment renders the card of the record of the model, see RenderTableFragment
+func (s *Service) RenderRecordFragment(ctx *model.Request, modelName string, recID int64, opts model.RenderOptions) (template.HTML, error) {
+	ctx, mConfig, opts, err := s.prepareFragment(ctx, modelName, "read", opts)
+	if err != nil {
+		return "", err
+	}
+
+	html, err := s.renderModelTableRecord(ctx, mConfig, recID, false, opts)
+	return template.HTML(html), err
+}
+
+// RenderCreateFormFragment renders the form creating a record of the model, see RenderTableFragment
+func (s *Service) RenderCreateFormFragment(ctx *model.Request, modelName string, opts model.RenderOptions) (template.HTML, error) {
+	ctx, mConfig, opts, err := s.prepareFragment(ctx, modelName, "create", opts)
+	if err != nil {
+		return "", err
+	}
+
+	html, err := s.renderModelTableRecordCreate(ctx, mConfig, "create", opts)
+	return template.HTML(html), err
+}
+
+// prepareFragment checks the access and loads the model config with the parent value of the options,
+// the filters are checked against the fields and extended by the parent connection field
+func (s *Service) prepareFragment(ctx *model.Request, modelName string, action string, opts model.RenderOptions) (*model.Request, *model.ConfigOfModel, model.RenderOptions, error) {
+	if ctx == nil {
+		r, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			return nil, nil, opts, err
+		}
+		ctx = model.NewRequest(r, nil)
+	}
+
+	if !s.Config.AccessCheckFunc(ctx, modelName, "", action) {
+		return nil, nil, opts, ErrAccessDenied
+	}
+
+	mConfig, err := s.prepareModelConfig(ctx, modelName, nil)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+
+	for field := range opts.Filters {
+		if !slices.Contains(mConfig.Fields, field) {
+			return nil, nil, opts, fmt.Errorf("filter field %s is not listed in the fields of model %s", field, modelName)
+		}
+	}
+
+	if opts.ParentValue != "" {
+		if mConfig.Parent.QueryVariableName == "" {
+			return nil, nil, opts, fmt.Errorf("model %s has no parent.queryVariableName for the parent value", modelName)
+		}
+		mConfig.AdditionalUrlParams = s.renderAdditionalUrlParams(ctx, mConfig, map[string]interface{}{mConfig.Parent.QueryVariableName: opts.ParentValue})
+
+		if mConfig.Parent.LocalConnectionField != "" {
+			filters := maps.Clone(opts.Filters)
+			if filters == nil {
+				filters = make(map[string]interface{})
+			}
+			filters[mConfig.Parent.LocalConnectionField] = opts.ParentValue
+			opts.Filters = filters
+		}
+	}
+
+	return ctx, mConfig, opts, nil
+}
+
+// optionalBreadcrumbs builds the breadcrumbs unless they are hidden by the options
+func (s *Service) optionalBreadcrumbs(mConfig *model.ConfigOfModel, recordLabel string, action string, opts model.RenderOptions) (template.HTML, error) {
+	if opts.HideBreadcrumbs {
+		return "", nil
+	}
+	return s.breadcrumbBuilder(mConfig, recordLabel, action)
+}
+
+// filtered is a gorm scope which selects the records with the field values
+func filtered(filters map[string]interface{}) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(filters) == 0 {
+			return db
+		}
+		return db.Where(filters)
+	}
+}
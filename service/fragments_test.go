@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestRenderFragments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"owners.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"]}`)},
+		"items.json": {Data: []byte(`{"pageTitle": "Items", "fields": ["id", "name", "owner_id"], "addableFields": ["name"],
+			"parent": {"modelName": "owners", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`INSERT INTO items (name, owner_id) VALUES ('apple', 1), ('pear', 2), ('plum', 2)`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return action != "create" || fieldName != ""
+	}
+
+	check := func(name string, html string, expected []string, unexpected []string) {
+		t.Helper()
+		for _, e := range expected {
+			if !strings.Contains(html, e) {
+				t.Errorf("%s: expected %q in\n%s", name, e, html)
+			}
+		}
+		for _, u := range unexpected {
+			if strings.Contains(html, u) {
+				t.Errorf("%s: unexpected %q in\n%s", name, u, html)
+			}
+		}
+	}
+
+	table, err := s.RenderTableFragment(nil, "items", model.RenderOptions{ParentValue: "2", HideBreadcrumbs: true})
+	if err != nil {
+		t.Fatalf("RenderTableFragment failed: %v", err)
+	}
+	check("parent", string(table), []string{"pear", "plum", `name="owner" value="2"`}, []string{"apple", "breadcrumb"})
+
+	s.Config.PaginationRecordsPerPage = 1
+	table, err = s.RenderTableFragment(nil, "items", model.RenderOptions{ParentValue: "2", HideBreadcrumbs: true})
+	s.Config.PaginationRecordsPerPage = 100
+	if err != nil {
+		t.Fatalf("RenderTableFragment failed: %v", err)
+	}
+	check("pagination", string(table), []string{`href="/wedyta/items?owner=2&amp;page=2"`}, []string{`href="items?`})
+
+	table, err = s.RenderTableFragment(nil, "items", model.RenderOptions{Filters: map[string]interface{}{"name": "apple"}, HideAddForm: true})
+	if err != nil {
+		t.Fatalf("RenderTableFragment failed: %v", err)
+	}
+	check("filters", string(table), []string{"apple", "breadcrumb"}, []string{"pear", "wedyta-add-form"})
+
+	if _, err := s.RenderTableFragment(nil, "items", model.RenderOptions{Filters: map[string]interface{}{"secret": 1}}); err == nil {
+		t.Errorf("expected the error of the unlisted filter field")
+	}
+
+	record, err := s.RenderRecordFragment(nil, "items", 3, model.RenderOptions{HideBreadcrumbs: true})
+	if err != nil {
+		t.Fatalf("RenderRecordFragment failed: %v", err)
+	}
+	check("record", string(record), []string{"plum"}, []string{"breadcrumb"})
+
+	if _, err := s.RenderCreateFormFragment(nil, "items", model.RenderOptions{}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool { return true }
+	form, err := s.RenderCreateFormFragment(nil, "items", model.RenderOptions{ParentValue: "1"})
+	if err != nil {
+		t.Fatalf("RenderCreateFormFragment failed: %v", err)
+	}
+	check("create form", string(form), []string{`name="owner" value="1"`, `href="/wedyta/items?owner=1"`}, nil)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/pa-pe/wedyta/embed"
+	"github.com/pa-pe/wedyta/model"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// localeCatalog has the translations of the UI texts, mapped by the English text
+type localeCatalog struct {
+	catalog    *catalog.Builder
+	messages   map[string]map[string]string // mapped by locale, for the scripts
+	defaultTag language.Tag
+	tags       []language.Tag // supported locales, the default one first
+	matcher    language.Matcher
+}
+
+// newLocaleCatalog builds the catalog of the embedded translations and the ones of WedytaConfig.Messages
+func newLocaleCatalog(defaultLocale string, configMessages map[string]map[string]string) (*localeCatalog, error) {
+	defaultTag, err := language.Parse(defaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DefaultLocale %q: %v", defaultLocale, err)
+	}
+
+	messages := make(map[string]map[string]string)
+	names, err := fs.Glob(embed.EmbeddedFiles, embed.LocalesPattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(name), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale of %s: %v", name, err)
+		}
+		data, err := fs.ReadFile(embed.EmbeddedFiles, name)
+		if err != nil {
+			return nil, err
+		}
+		texts := make(map[string]string)
+		if err := json.Unmarshal(data, &texts); err != nil {
+			return nil, fmt.Errorf("can't parse %s: %v", name, err)
+		}
+		messages[tag.String()] = texts
+	}
+
+	for locale, texts := range configMessages {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q of Messages: %v", locale, err)
+		}
+		if messages[tag.String()] == nil {
+			messages[tag.String()] = make(map[string]string)
+		}
+		for text, translation := range texts {
+			messages[tag.String()][text] = translation
+		}
+	}
+
+	c := &localeCatalog{
+		catalog:    catalog.NewBuilder(catalog.Fallback(defaultTag)),
+		messages:   messages,
+		defaultTag: defaultTag,
+		tags:       []language.Tag{defaultTag},
+	}
+	for _, locale := range mapKeys(messages) {
+		tag := language.Make(locale)
+		for text, translation := range messages[locale] {
+			if err := c.catalog.SetString(tag, text, translation); err != nil {
+				return nil, fmt.Errorf("invalid translation of %q to %s: %v", text, tag, err)
+			}
+		}
+		if tag != defaultTag {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	c.matcher = language.NewMatcher(c.tags)
+
+	return c, nil
+}
+
+// match returns the supported locale closest to the requested ones, the default locale if none is close
+func (c *localeCatalog) match(requested ...language.Tag) language.Tag {
+	_, index, confidence := c.matcher.Match(requested...)
+	if confidence == language.No {
+		return c.defaultTag
+	}
+	return c.tags[index]
+}
+
+// translate formats the text translated to the locale, the text itself is used if it has no translation
+func (c *localeCatalog) translate(locale language.Tag, text string, args ...interface{}) string {
+	if locale == language.Und {
+		locale = c.defaultTag
+	}
+	return message.NewPrinter(locale, message.Catalog(c.catalog)).Sprintf(text, args...)
+}
+
+// scriptMessages are the translations passed to the scripts of the page as window.wedytaMessages
+func (c *localeCatalog) scriptMessages(locale language.Tag) map[string]string {
+	if locale == language.Und {
+		locale = c.defaultTag
+	}
+	if texts, found := c.messages[locale.String()]; found {
+		return texts
+	}
+	return map[string]string{}
+}
+
+// pageLang is the lang attribute of the page in the locale
+func (c *localeCatalog) pageLang(locale language.Tag) string {
+	if locale == language.Und {
+		locale = c.defaultTag
+	}
+	return locale.String()
+}
+
+// resolveLocale returns the locale of the request by WedytaConfig.LocaleResolver or the Accept-Language header
+func (s *Service) resolveLocale(ctx *model.Request) language.Tag {
+	if s.Config.LocaleResolver != nil {
+		tag, err := language.Parse(s.Config.LocaleResolver(ctx))
+		if err != nil {
+			return s.i18n.defaultTag
+		}
+		return s.i18n.match(tag)
+	}
+
+	if ctx.Request == nil {
+		return s.i18n.defaultTag
+	}
+	requested, _, err := language.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	if err != nil || len(requested) == 0 {
+		return s.i18n.defaultTag
+	}
+	return s.i18n.match(requested...)
+}
+
+// translate translates the UI text to the locale of the model config
+func (s *Service) translate(mConfig *model.ConfigOfModel, text string, args ...interface{}) string {
+	return s.i18n.translate(modelLocale(mConfig), text, args...)
+}
+
+func modelLocale(mConfig *model.ConfigOfModel) language.Tag {
+	if mConfig == nil {
+		return language.Und
+	}
+	return mConfig.Locale
+}
+
+// localizeModelConfig returns a copy of the cached config for the request in the locale, with the texts of its "locales" key.
+// The copy of the parent config is localized too.
+func (s *Service) localizeModelConfig(mConfig *model.ConfigOfModel, locale language.Tag) *model.ConfigOfModel {
+	localized := *mConfig
+	localized.Locale = locale
+	if mConfig.ParentConfig != nil {
+		localized.ParentConfig = s.localizeModelConfig(mConfig.ParentConfig, locale)
+	}
+
+	texts, found := lookupLocaleConfig(mConfig.Locales, locale)
+	if !found {
+		return &localized
+	}
+
+	if texts.PageTitle != "" {
+		localized.PageTitle = texts.PageTitle
+	}
+	if texts.Group != "" {
+		localized.Group = texts.Group
+	}
+	if len(texts.Headers) > 0 || len(texts.Titles) > 0 {
+		localized.FieldConfig = make(map[string]model.FieldParams, len(mConfig.FieldConfig))
+		for field, param := range mConfig.FieldConfig {
+			if header := texts.Headers[field]; header != "" {
+				param.Header = header
+			}
+			if title := texts.Titles[field]; title != "" {
+				param.Title = title
+			}
+			localized.FieldConfig[field] = param
+		}
+	}
+
+	return &localized
+}
+
+// lookupLocaleConfig finds the texts of the locale, or of its language if there are none of the region, e.g. "pt" for pt-BR
+func lookupLocaleConfig(locales map[string]model.LocaleConfig, locale language.Tag) (model.LocaleConfig, bool) {
+	if len(locales) == 0 {
+		return model.LocaleConfig{}, false
+	}
+	if texts, found := locales[locale.String()]; found {
+		return texts, true
+	}
+	base, _ := locale.Base()
+	texts, found := locales[base.String()]
+	return texts, found
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLocalization(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT); INSERT INTO items (name) VALUES ('apple');`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	locale := ""
+	s := NewService(db, &model.WedytaConfig{
+		AssetsSource: model.AssetsSourceCDN,
+		ConfigFS: fstest.MapFS{
+			"items.json": {Data: []byte(`{"pageTitle": "Items", "fields": ["id", "name"], "headers": {"name": "Name"}, "addableFields": ["name"],
+				"locales": {"ru": {"pageTitle": "Товары", "headers": {"name": "Название"}}, "de": {"pageTitle": "Artikel"}}}`)},
+			"broken.json": {Data: []byte(`{"dbTable": "items", "fields": ["id"], "locales": {"ru": {"headers": {"name": "Название"}}}}`)},
+		},
+		Messages: map[string]map[string]string{
+			"de": {"Add New Record": "Neuer Datensatz"},
+			"ru": {"Create": "Добавить"},
+		},
+	})
+
+	render := func(acceptLanguage string) string {
+		ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/items", nil), map[string]string{"modelName": "items"})
+		ctx.Request.Header.Set("Accept-Language", acceptLanguage)
+		s.RenderTable(ctx)
+		return string(ctx.Response.Body)
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		expected       []string
+		unexpected     []string
+	}{
+		{"ru-RU,ru;q=0.9,en;q=0.8", []string{`<html lang="ru">`, "<h2>Товары</h2>", `id="header_of_name">Название</th>`, "Добавить запись", `>Добавить</button>`,
+			`"Cancel":"Отмена"`}, []string{"&nbsp; Add New Record", "Items"}},
+		{"de", []string{`<html lang="de">`, "<h2>Artikel</h2>", `id="header_of_name">Name</th>`, "Neuer Datensatz", `>Create</button>`}, nil},
+		{"fr, en;q=0.5", []string{`<html lang="en">`, "<h2>Items</h2>", "Add New Record", "window.wedytaMessages = {}"}, []string{"Товары"}},
+	}
+	for _, test := range tests {
+		body := render(test.acceptLanguage)
+		for _, expected := range test.expected {
+			if !strings.Contains(body, expected) {
+				t.Errorf("%s: expected %q in\n%s", test.acceptLanguage, expected, body)
+			}
+		}
+		for _, unexpected := range test.unexpected {
+			if strings.Contains(body, unexpected) {
+				t.Errorf("%s: unexpected %q", test.acceptLanguage, unexpected)
+			}
+		}
+	}
+
+	// the resolver takes precedence over the header, the cached config stays untranslated
+	s.Config.LocaleResolver = func(ctx *model.Request) string { return locale }
+	locale = "ru"
+	if body := render("de"); !strings.Contains(body, "<h2>Товары</h2>") {
+		t.Errorf("expected the locale of LocaleResolver")
+	}
+	if cached, _ := s.cachedModelConfig("items"); cached.PageTitle != "Items" || cached.FieldConfig["name"].Header != "Name" {
+		t.Errorf("expected the cached config not to be localized, got %q", cached.PageTitle)
+	}
+
+	issues := s.ValidateModel("broken")
+	if len(issues) != 1 || issues[0].Path != "locales.ru.headers.name" {
+		t.Errorf("expected the issue of the unlisted field, got:\n%v", issues.Error())
+	}
+}
@@ -4,76 +4,147 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"regexp"
+	"slices"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils"
 	"github.com/pa-pe/wedyta/utils/sqlutils"
 )
 
-func (s *Service) loadModelConfig(ctx *gin.Context, modelName string, payload map[string]interface{}) *model.ConfigOfModel {
-	configPath := s.Config.ConfigDir + "/" + modelName + ".json"
-
-	stat, err := os.Stat(configPath)
+func (s *Service) loadModelConfig(ctx *model.Request, modelName string, payload map[string]interface{}) *model.ConfigOfModel {
+	mConfig, err := s.prepareModelConfig(ctx, modelName, payload)
 	if err != nil {
-		s.SomethingWentWrong(ctx, fmt.Sprintf("Cannot stat mConfig file for model %s: %v", modelName, err))
+		s.SomethingWentWrong(ctx, err.Error())
 		return nil
 	}
 
+	return mConfig
+}
+
+// prepareModelConfig does the same as loadModelConfig but returns the error instead of writing it to the response.
+// The config is a copy of the cached one in the locale of the request.
+func (s *Service) prepareModelConfig(ctx *model.Request, modelName string, payload map[string]interface{}) (*model.ConfigOfModel, error) {
+	if isConfigFragment(modelName) {
+		return nil, fmt.Errorf("Model %s not found, the configs starting with %q are fragments of extends", modelName, fragmentPrefix)
+	}
+
+	s.cacheMu.RLock()
+	issues, unavailable := s.unavailableModels[modelName]
+	s.cacheMu.RUnlock()
+	if unavailable {
+		return nil, fmt.Errorf("Model %s is unavailable due to config issues:\n%v", modelName, issues.Error())
+	}
+
+	cached, err := s.cachedModelConfig(modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	mConfig := s.localizeModelConfig(cached, s.resolveLocale(ctx))
+	s.refreshVariableDependentParams(ctx, mConfig, payload)
+
+	return mConfig, nil
+}
+
+// cachedModelConfig returns the cached config of the model, reading it if it's absent or its files have changed
+func (s *Service) cachedModelConfig(modelName string) (*model.ConfigOfModel, error) {
+	return s.cachedChainModelConfig(modelName, nil)
+}
+
+// cachedChainModelConfig is cachedModelConfig of the parent read for the chain of its children
+func (s *Service) cachedChainModelConfig(modelName string, chain []string) (*model.ConfigOfModel, error) {
+	s.cacheMu.RLock()
 	cached, found := s.modelCache[modelName]
-	if found && cached.ModTime.Equal(stat.ModTime()) {
-		s.refreshVariableDependentParams(ctx, cached.Config, payload)
-		return cached.Config
+	s.cacheMu.RUnlock()
+	if found && s.cacheIsFresh(modelName, cached) {
+		return cached.Config, nil
 	}
 
-	data, err := os.ReadFile(configPath)
+	cached, err := s.readModelConfig(modelName, chain, s.cachedChainModelConfig)
 	if err != nil {
-		s.SomethingWentWrong(ctx, fmt.Sprintf("No configuration found for modelName: %s, err: %v", modelName, err))
-		return nil
+		return nil, err
 	}
 
-	var mConfig model.ConfigOfModel
-	if err := json.Unmarshal(data, &mConfig); err != nil {
-		//return nil, fmt.Errorf("failed to parse mConfig JSON: %w", err)
-		s.SomethingWentWrong(ctx, fmt.Sprintf("Failed to parse mConfig JSON of modelName: %s, err: %v", modelName, err))
-		return nil
+	s.cacheMu.Lock()
+	s.modelCache[modelName] = cached
+	s.cacheMu.Unlock()
+
+	return cached.Config, nil
+}
+
+// cacheIsFresh reports whether the cached config is up to date, the files are not checked while the watcher reloads them
+func (s *Service) cacheIsFresh(modelName string, cached model.CachedModelConfig) bool {
+	if s.watcher != nil {
+		return true
+	}
+
+	// a config file added over the registered struct
+	if configName, _, err := s.findConfigFile(modelName); err == nil && cached.FileVersions[configName] == "" {
+		return false
+	}
+
+	return !s.configFilesChanged(cached.FileVersions)
+}
+
+// readModelConfig reads the model config with defaults, the parent config is taken from parentConfig.
+// The chain lists the children being read, the parent found in it is a cycle.
+func (s *Service) readModelConfig(modelName string, chain []string, parentConfig func(modelName string, chain []string) (*model.ConfigOfModel, error)) (model.CachedModelConfig, error) {
+	if slices.Contains(chain, modelName) {
+		return model.CachedModelConfig{}, fmt.Errorf("Parent cycle: %s -> %s", strings.Join(chain, " -> "), modelName)
+	}
+	chain = append(chain, modelName)
+
+	// a config file takes precedence over the registered struct of the same name
+	configName, _, err := s.findConfigFile(modelName)
+	reg, registered := lookupRegisteredModel(modelName)
+	if err != nil && !registered {
+		return model.CachedModelConfig{}, fmt.Errorf("Cannot find mConfig file for model %s: %v", modelName, err)
+	}
+
+	var mConfig *model.ConfigOfModel
+	var fileVersions map[string]string
+	if configName != "" {
+		mConfig, fileVersions, err = s.readModelConfigFile(modelName)
+		if err != nil {
+			return model.CachedModelConfig{}, fmt.Errorf("Failed to parse mConfig %s of modelName: %s, err: %v", configName, modelName, err)
+		}
+	} else {
+		mConfig, err = s.buildRegisteredModelConfig(reg)
+		if err != nil {
+			return model.CachedModelConfig{}, fmt.Errorf("Failed to build mConfig of registered modelName: %s, err: %v", modelName, err)
+		}
 	}
 
 	mConfig.ModelName = modelName
-	s.loadModelConfigDefaults(&mConfig)
-	s.fillFieldConfig(&mConfig)
+	s.loadModelConfigDefaults(mConfig)
+	if err := s.fillFieldConfig(mConfig); err != nil {
+		return model.CachedModelConfig{}, fmt.Errorf("Invalid mConfig of modelName: %s, err: %v", modelName, err)
+	}
 
 	if mConfig.Parent.ModelName != "" {
-		//fmt.Println(parentModelName)
-		mConfig.ParentConfig = s.loadModelConfig(ctx, mConfig.Parent.ModelName, payload)
-		if mConfig.ParentConfig == nil {
-			s.SomethingWentWrong(ctx, "Can`t load ParentConfig: "+mConfig.Parent.ModelName)
-			return nil
+		mConfig.ParentConfig, err = parentConfig(mConfig.Parent.ModelName, chain)
+		if err != nil {
+			return model.CachedModelConfig{}, fmt.Errorf("Can`t load ParentConfig: %s, err: %v", mConfig.Parent.ModelName, err)
 		}
 		mConfig.HasParent = true
 	}
 
 	if s.Config.VariableResolver == nil && strings.Contains(mConfig.SqlWhere, "{{") {
-		s.SomethingWentWrong(ctx, fmt.Sprintf("Trying to use variables without wedytaConfig.VariableResolver modelName=%s", modelName))
-		return nil
+		return model.CachedModelConfig{}, fmt.Errorf("Trying to use variables without wedytaConfig.VariableResolver modelName=%s", modelName)
 	}
 
 	//identifyInsertModeHiddenFields(&mConfig)
 
-	s.modelCache[modelName] = model.CachedModelConfig{
-		Config:  &mConfig,
-		ModTime: stat.ModTime(),
-	}
-
-	s.refreshVariableDependentParams(ctx, &mConfig, payload)
-
-	return &mConfig
+	return model.CachedModelConfig{Config: mConfig, FileVersions: fileVersions}, nil
 }
 
-func (s *Service) refreshVariableDependentParams(ctx *gin.Context, mConfig *model.ConfigOfModel, payload map[string]interface{}) {
+func (s *Service) refreshVariableDependentParams(ctx *model.Request, mConfig *model.ConfigOfModel, payload map[string]interface{}) {
+	if mConfig.HasParent {
+		s.refreshVariableDependentParams(ctx, mConfig.ParentConfig, payload)
+	}
+
 	mConfig.SqlWhere = s.resolveVariables(ctx, mConfig.ModelName, mConfig.SqlWhereOriginal)
 
 	if mConfig.HasParent {
@@ -81,7 +152,7 @@ func (s *Service) refreshVariableDependentParams(ctx *gin.Context, mConfig *mode
 	}
 }
 
-func (s *Service) renderAdditionalUrlParams(ctx *gin.Context, mConfig *model.ConfigOfModel, payload map[string]interface{}) string {
+func (s *Service) renderAdditionalUrlParams(ctx *model.Request, mConfig *model.ConfigOfModel, payload map[string]interface{}) string {
 	additionalUrlParams := "?"
 
 	if mConfig.HasParent {
@@ -121,16 +192,18 @@ func (s *Service) loadModelConfigDefaults(mConfig *model.ConfigOfModel) {
 		mConfig.DbTable = utils.CamelToSnake(mConfig.ModelName)
 	}
 
-	var err error
-	mConfig.DbTablePrimaryKey, err = sqlutils.GetPrimaryKeyFieldName(s.DB, mConfig.DbTable)
-	if err != nil {
-		log.Printf("WeDyTa: can't determine primary key for table %s: %v", mConfig.DbTable, err)
+	if mConfig.DbTablePrimaryKey == "" {
+		var err error
+		mConfig.DbTablePrimaryKey, err = sqlutils.GetPrimaryKeyFieldName(s.DB, mConfig.DbTable)
+		if err != nil {
+			log.Printf("WeDyTa: can't determine primary key for table %s: %v", mConfig.DbTable, err)
+		}
 	}
 
 	mConfig.HeaderTags = `<link rel="stylesheet" href="/wedyta/static/css/wedyta.css">` + "\n"
 }
 
-func (s *Service) fillFieldConfig(mConfig *model.ConfigOfModel) {
+func (s *Service) fillFieldConfig(mConfig *model.ConfigOfModel) error {
 	if mConfig.FieldConfig == nil {
 		mConfig.FieldConfig = make(map[string]model.FieldParams)
 	}
@@ -230,7 +303,7 @@ func (s *Service) fillFieldConfig(mConfig *model.ConfigOfModel) {
 		if field == "is_active" {
 			if _, exist := mConfig.FieldEditor[field]; !exist {
 				if mConfig.FieldEditor == nil {
-					mConfig.FieldEditor = make(map[string]map[string]interface{})
+					mConfig.FieldEditor = make(map[string]model.FieldEditorConfig)
 				}
 
 				mConfig.FieldEditor[field] = map[string]interface{}{
@@ -249,10 +322,10 @@ func (s *Service) fillFieldConfig(mConfig *model.ConfigOfModel) {
 			if typStr, ok := typRaw.(string); ok {
 				param.FieldEditor = typStr
 			} else {
-				log.Fatalf("WeDyTa: fieldsEditor.type must be string for field %s", field)
+				return fmt.Errorf("fieldsEditor.type must be string for field %s", field)
 			}
 		} else {
-			log.Fatalf("WeDyTa: no type for field %s in fieldsEditor", field)
+			return fmt.Errorf("no type for field %s in fieldsEditor", field)
 		}
 		mConfig.FieldConfig[field] = param
 
@@ -313,9 +386,11 @@ func (s *Service) fillFieldConfig(mConfig *model.ConfigOfModel) {
 			mConfig.Links[field] = linkConfig
 		}
 	}
+
+	return nil
 }
 
-func (s *Service) resolveVariables(ctx *gin.Context, modelName string, str string) string {
+func (s *Service) resolveVariables(ctx *model.Request, modelName string, str string) string {
 	if !strings.Contains(str, "{{") {
 		return str
 	}
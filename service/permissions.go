@@ -0,0 +1,78 @@
+package service
+
+import (
+	"log"
+	"slices"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+// permissionsAnyRole is the role key of the "permissions" matrix which applies to every user
+const permissionsAnyRole = "*"
+
+// defaultAccessCheck is used as AccessCheckFunc when the application does not provide its own,
+// it evaluates the "permissions" matrix of the model config against the roles returned by RoleResolver
+func (s *Service) defaultAccessCheck(ctx *model.Request, modelName, fieldName, action string) bool {
+	// the matrix doesn't depend on the request, the cached config is read without the preparation for the request
+	mConfig, err := s.cachedModelConfig(modelName)
+	if err != nil {
+		log.Printf("WeDyTa: access check for model %s: %v", modelName, err)
+		return false
+	}
+
+	if len(mConfig.Permissions) == 0 {
+		return true // no matrix - permit all
+	}
+
+	var roles []string
+	if s.Config.RoleResolver != nil {
+		roles = s.Config.RoleResolver(ctx)
+	}
+
+	return permissionMatrixAllows(mConfig.Permissions, roles, fieldName, action)
+}
+
+// permissionMatrixAllows returns true if at least one of the roles (or the "*" role) permits the action on the field
+func permissionMatrixAllows(permissions map[string]model.PermissionConfig, roles []string, fieldName, action string) bool {
+	for _, role := range append([]string{permissionsAnyRole}, roles...) {
+		permission, exists := permissions[role]
+		if !exists {
+			continue
+		}
+
+		if !slices.Contains(permission.Actions, action) && !slices.Contains(permission.Actions, "*") {
+			continue
+		}
+
+		if fieldName == "" {
+			return true
+		}
+
+		fields, exists := permission.Fields[action]
+		if !exists || slices.Contains(fields, fieldName) || slices.Contains(fields, "*") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldPermitted calls AccessCheckFunc for the field, the result is remembered in the cache for the time of rendering
+func (s *Service) fieldPermitted(ctx *model.Request, mConfig *model.ConfigOfModel, field, action string, cache *model.RenderTableCache) bool {
+	if cache == nil {
+		return s.Config.AccessCheckFunc(ctx, mConfig.ModelName, field, action)
+	}
+
+	if cache.Permissions == nil {
+		cache.Permissions = make(map[string]bool)
+	}
+
+	cacheKey := action + ":" + field
+	if permitted, found := cache.Permissions[cacheKey]; found {
+		return permitted
+	}
+
+	permitted := s.Config.AccessCheckFunc(ctx, mConfig.ModelName, field, action)
+	cache.Permissions[cacheKey] = permitted
+	return permitted
+}
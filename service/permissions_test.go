@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestPermissionMatrixAllows(t *testing.T) {
+	permissions := map[string]model.PermissionConfig{
+		"*":      {Actions: []string{"read"}},
+		"admin":  {Actions: []string{"*"}},
+		"editor": {Actions: []string{"read", "update"}, Fields: map[string][]string{"update": {"title", "body"}}},
+	}
+
+	tests := []struct {
+		roles  []string
+		field  string
+		action string
+		expect bool
+	}{
+		{roles: nil, field: "", action: "read", expect: true},
+		{roles: nil, field: "title", action: "read", expect: true},
+		{roles: nil, field: "", action: "update", expect: false},
+		{roles: []string{"editor"}, field: "", action: "update", expect: true},
+		{roles: []string{"editor"}, field: "title", action: "update", expect: true},
+		{roles: []string{"editor"}, field: "price", action: "update", expect: false},
+		{roles: []string{"editor"}, field: "", action: "delete", expect: false},
+		{roles: []string{"editor", "admin"}, field: "price", action: "update", expect: true},
+		{roles: []string{"admin"}, field: "", action: "purge", expect: true},
+		{roles: []string{"unknown"}, field: "", action: "create", expect: false},
+	}
+
+	for i, tt := range tests {
+		got := permissionMatrixAllows(permissions, tt.roles, tt.field, tt.action)
+		if got != tt.expect {
+			t.Errorf("test %d: roles=%v field=%q action=%q: expected %v, got %v", i, tt.roles, tt.field, tt.action, tt.expect, got)
+		}
+	}
+}
+
+func TestDefaultAccessCheck(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"fields": ["id", "name"], "sqlWhere": "owner_id = {{owner}}",
+			"permissions": {"*": {"actions": ["read"]}, "editor": {"actions": ["update"], "fields": {"update": ["name"]}}}}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+	resolved := 0
+	s.Config.VariableResolver = func(ctx *model.Request, modelName, variableName string) string {
+		resolved++
+		return "1"
+	}
+	s.Config.RoleResolver = func(ctx *model.Request) []string { return []string{"editor"} }
+
+	if !s.Config.AccessCheckFunc(ctx, "items", "name", "update") || s.Config.AccessCheckFunc(ctx, "items", "id", "update") || s.Config.AccessCheckFunc(ctx, "items", "", "delete") {
+		t.Errorf("unexpected result of the permissions matrix")
+	}
+	if resolved != 0 {
+		t.Errorf("expected the access check not to prepare the config for the request, the variables were resolved %d times", resolved)
+	}
+}
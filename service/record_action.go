@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+// RegisterRecordAction makes the action available to the models listing its name in "recordActions",
+// it's shown as a button in the stdRecordControls column and executed by POST /wedyta/action.
+// It fails if the name is registered already or the action has no Handler. NewServiceE registers WedytaConfig.RecordActions
+// before the configs are validated, the actions registered later are seen by the validation of Reload.
+func (s *Service) RegisterRecordAction(name string, action model.RecordAction) error {
+	if action.Handler == nil {
+		return fmt.Errorf("WeDyTa: record action %q has no Handler", name)
+	}
+	if action.Permission == "" {
+		action.Permission = name
+	}
+
+	s.recordActionsMu.Lock()
+	defer s.recordActionsMu.Unlock()
+	if _, exists := s.recordActions[name]; exists {
+		return fmt.Errorf("WeDyTa: record action %q is registered already", name)
+	}
+	if s.recordActions == nil {
+		s.recordActions = make(map[string]model.RecordAction)
+	}
+	s.recordActions[name] = action
+	return nil
+}
+
+func (s *Service) lookupRecordAction(name string) (model.RecordAction, bool) {
+	s.recordActionsMu.RLock()
+	defer s.recordActionsMu.RUnlock()
+	action, found := s.recordActions[name]
+	return action, found
+}
+
+// HandleRecordAction executes the registered action listed in "recordActions" of the model, {"modelName": ..., "action": ..., "id": ...}
+func (s *Service) HandleRecordAction(ctx *model.Request) {
+	var payload map[string]interface{}
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Invalid JSON"})
+		return
+	}
+
+	name, _ := payload["action"].(string)
+	action, found := s.lookupRecordAction(name)
+	if !found {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("Unknown record action '%s'", name)})
+		return
+	}
+
+	mConfig, id, ok := s.recordActionTarget(ctx, payload, action.Permission)
+	if !ok {
+		return
+	}
+
+	if !slices.Contains(mConfig.RecordActions, name) {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("Unknown record action '%s'", name)})
+		return
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// checks that the record is accessible by sqlWhere
+		if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(notSoftDeleted(mConfig)).Take(&map[string]interface{}{}).Error; err != nil {
+			return newHttpError(http.StatusNotFound, "Record not found")
+		}
+
+		if err := action.Handler(ctx, tx, mConfig.DbTable, id); err != nil {
+			return newHttpError(http.StatusBadRequest, err.Error())
+		}
+
+		return s.writeAudit(ctx, tx, mConfig, id, name, nil)
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	s.fireAfterCommit(ctx, mConfig, name, id, nil)
+
+	ctx.JSON(http.StatusOK, model.H{"success": true, "message": "Action completed successfully"})
+}
+
+// renderRecordActions renders the buttons of the permitted "recordActions" of the model for the stdRecordControls column
+func (s *Service) renderRecordActions(ctx *model.Request, mConfig *model.ConfigOfModel, pkValue string, cache *model.RenderTableCache) string {
+	var buttons strings.Builder
+	for _, name := range mConfig.RecordActions {
+		action, found := s.lookupRecordAction(name)
+		if !found {
+			log.Printf("WeDyTa: record action %q of model %s is not registered", name, mConfig.ModelName)
+			continue
+		}
+		if !s.fieldPermitted(ctx, mConfig, "", action.Permission, cache) {
+			continue
+		}
+
+		label := name
+		if action.Label != "" {
+			label = s.translate(mConfig, action.Label)
+		}
+		confirm := ""
+		if action.Confirm != "" {
+			confirm = s.translate(mConfig, action.Confirm)
+		}
+
+		buttons.WriteString(" <button type=\"button\" class=\"btn btn-sm btn-outline-secondary record-action\" action=\"" + template.HTMLEscapeString(name) +
+			"\" rec_id=\"" + pkValue + "\" confirm=\"" + template.HTMLEscapeString(confirm) + "\">")
+		if action.Icon != "" {
+			buttons.WriteString("<i class=\"" + template.HTMLEscapeString(action.Icon) + "\"></i> ")
+		}
+		buttons.WriteString(template.HTMLEscapeString(label) + "</button>")
+	}
+	return buttons.String()
+}
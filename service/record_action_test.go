@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var recordActions = map[string]model.RecordAction{
+	"approve": {Label: "Approve", Icon: "bi-check", Confirm: "Approve record #{id}?", Handler: func(ctx *model.Request, db *gorm.DB, table string, id int64) error {
+		if err := db.Table(table).Where("id = ?", id).Update("name", "approved").Error; err != nil {
+			return err
+		}
+		if id == 2 {
+			return errors.New("pears can't be approved")
+		}
+		return nil
+	}},
+	"archive": {Label: "Archive", Permission: "archive items", Handler: func(ctx *model.Request, db *gorm.DB, table string, id int64) error {
+		return nil
+	}},
+}
+
+func TestRecordActions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json":  {Data: []byte(`{"fields": ["id", "name", "controls"], "columnDataFunc": {"controls": "stdRecordControls"}, "recordActions": ["approve", "archive"]}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"], "recordActions": ["approve", "unknown"]}`)},
+		"granted.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "controls"], "columnDataFunc": {"controls": "stdRecordControls"}, "recordActions": ["archive"],
+			"permissions": {"*": {"actions": ["read", "archive items", "bogus"]}}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`INSERT INTO items (name) VALUES ('apple'), ('pear')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	for name, action := range recordActions {
+		if err := s.RegisterRecordAction(name, action); err != nil {
+			t.Fatalf("RegisterRecordAction failed: %v", err)
+		}
+	}
+	if err := s.RegisterRecordAction("approve", recordActions["approve"]); err == nil {
+		t.Errorf("expected the error of the registered name")
+	}
+	if err := s.RegisterRecordAction("noop", model.RecordAction{Label: "Noop"}); err == nil {
+		t.Errorf("expected the error of the action without a Handler")
+	}
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return action != "archive items"
+	}
+
+	perform := func(payload string) int {
+		ctx := model.NewRequest(httptest.NewRequest("POST", "/wedyta/action", strings.NewReader(payload)), nil)
+		s.HandleRecordAction(ctx)
+		return ctx.Response.Status
+	}
+	name := func(id int) string {
+		var value string
+		s.DB.Table("items").Where("id = ?", id).Pluck("name", &value)
+		return value
+	}
+
+	ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/items", nil), map[string]string{"modelName": "items"})
+	s.RenderTable(ctx)
+	table := string(ctx.Response.Body)
+	if !strings.Contains(table, `class="btn btn-sm btn-outline-secondary record-action" action="approve" rec_id="1" confirm="Approve record #{id}?"><i class="bi-check"></i> Approve</button>`) {
+		t.Errorf("expected the approve button in\n%s", table)
+	}
+	if strings.Contains(table, `action="archive"`) {
+		t.Errorf("unexpected denied archive button in\n%s", table)
+	}
+
+	tests := []struct {
+		payload string
+		status  int
+	}{
+		{`{"modelName": "items", "action": "approve", "id": 1}`, http.StatusOK},
+		{`{"modelName": "items", "action": "approve", "id": "2"}`, http.StatusBadRequest},
+		{`{"modelName": "items", "action": "approve", "id": 99}`, http.StatusNotFound},
+		{`{"modelName": "items", "action": "archive", "id": 1}`, http.StatusForbidden},
+		{`{"modelName": "items", "action": "unknown", "id": 1}`, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		if status := perform(test.payload); status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.payload, test.status, status)
+		}
+	}
+	if name(1) != "approved" || name(2) != "pear" {
+		t.Errorf("expected the failed action to be rolled back, got %q, %q", name(1), name(2))
+	}
+
+	issues := s.ValidateModel("broken")
+	if len(issues) != 2 || issues[0].Path != "recordActions.1" || issues[1].Path != "recordActions" {
+		t.Errorf("expected the issue of the record actions, got:\n%v", issues.Error())
+	}
+
+	issues = s.ValidateModel("granted")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `"bogus"`) {
+		t.Errorf("expected the permission of the record action to be known, got:\n%v", issues.Error())
+	}
+}
+
+func TestRecordActions_StrictValidation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"fields": ["id", "controls"], "columnDataFunc": {"controls": "stdRecordControls"}, "recordActions": ["archive"],
+			"permissions": {"*": {"actions": ["read", "archive items"]}}}`)},
+	}
+
+	// the actions are known to the validation of NewServiceE
+	_, err = NewServiceE(db, &model.WedytaConfig{ConfigFS: fsys, AssetsSource: model.AssetsSourceCDN, ConfigValidation: model.ConfigValidationStrict, RecordActions: recordActions})
+	if err != nil {
+		t.Errorf("expected the configured record action to be valid, got: %v", err)
+	}
+	_, err = NewServiceE(db, &model.WedytaConfig{ConfigFS: fsys, AssetsSource: model.AssetsSourceCDN, ConfigValidation: model.ConfigValidationStrict})
+	if err == nil || !strings.Contains(err.Error(), `unknown record action "archive"`) {
+		t.Errorf("expected the unknown record action, got: %v", err)
+	}
+}
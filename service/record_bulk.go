@@ -0,0 +1,303 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
+)
+
+// built-in actions of "bulkActions", other names are looked up in WedytaConfig.BulkActions
+const (
+	bulkActionDelete   = "delete"
+	bulkActionEnable   = "enable"
+	bulkActionDisable  = "disable"
+	bulkActionSetField = "setField"
+)
+
+// bulkSwitchField is the field switched by the enable and disable bulk actions, shown by bs5switch
+const bulkSwitchField = "is_active"
+
+var builtinBulkActions = []string{bulkActionDelete, bulkActionEnable, bulkActionDisable, bulkActionSetField}
+
+var builtinBulkActionTitles = map[string]string{
+	bulkActionDelete:   "Delete",
+	bulkActionEnable:   "Enable",
+	bulkActionDisable:  "Disable",
+	bulkActionSetField: "Set field",
+}
+
+// bulkFailure is a record the bulk action failed on
+type bulkFailure struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error"`
+}
+
+// bulkOperation applies the action to a record inside the transaction, returning the changes for the audit
+type bulkOperation func(tx *gorm.DB, id int64) ([]model.FieldChange, error)
+
+// Bulk applies the action of "bulkActions" to the selected records, {"modelName": ..., "action": ..., "ids": [...]},
+// setField also takes "field" and "value". The records are processed in one transaction, a failed record is rolled back
+// to its savepoint and reported in the summary while the others are committed.
+func (s *Service) Bulk(ctx *model.Request) {
+	var payload map[string]interface{}
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Invalid JSON"})
+		return
+	}
+
+	modelName, ok := payload["modelName"].(string)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Model name is required"})
+		return
+	}
+
+	action, _ := payload["action"].(string)
+	if s.Config.AccessCheckFunc(ctx, modelName, "", bulkAccessAction(action)) != true {
+		ctx.JSON(http.StatusForbidden, model.H{"error": "Access denied", "modelName": modelName})
+		return
+	}
+
+	mConfig := s.loadModelConfig(ctx, modelName, payload)
+	if mConfig == nil {
+		return
+	}
+
+	if !slices.Contains(mConfig.BulkActions, action) {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("Unknown bulk action '%s'", action)})
+		return
+	}
+
+	ids, err := getIdsFromPayload(payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": err.Error()})
+		return
+	}
+
+	operation, auditAction, ok := s.bulkOperation(ctx, mConfig, action, payload)
+	if !ok {
+		return
+	}
+
+	succeeded := []int64{}
+	failed := []bulkFailure{}
+	changesByID := make(map[int64][]model.FieldChange)
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			var changes []model.FieldChange
+			// the nested transaction is a savepoint, the failed record doesn't roll back the others
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				var err error
+				changes, err = operation(tx, id)
+				return err
+			})
+			if err != nil {
+				failed = append(failed, bulkFailure{ID: id, Error: transactionErrorMessage(err)})
+				continue
+			}
+			succeeded = append(succeeded, id)
+			changesByID[id] = changes
+		}
+		return nil
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	for _, id := range succeeded {
+		// the records having the value already aren't updated
+		if auditAction == "update" && len(changesByID[id]) == 0 {
+			continue
+		}
+		s.fireAfterCommit(ctx, mConfig, auditAction, id, changesByID[id])
+	}
+
+	ctx.JSON(http.StatusOK, model.H{"success": len(failed) == 0, "succeeded": succeeded, "failed": failed})
+}
+
+// bulkAccessAction is the action checked by AccessCheckFunc for the bulk action
+func bulkAccessAction(action string) string {
+	switch action {
+	case bulkActionDelete:
+		return "delete"
+	case bulkActionEnable, bulkActionDisable, bulkActionSetField:
+		return "update"
+	default:
+		return action
+	}
+}
+
+// bulkOperation prepares the operation of the action, the errors of the request are written to the response
+func (s *Service) bulkOperation(ctx *model.Request, mConfig *model.ConfigOfModel, action string, payload map[string]interface{}) (bulkOperation, string, bool) {
+	switch action {
+	case bulkActionDelete:
+		if !mConfig.Deletable {
+			ctx.JSON(http.StatusForbidden, model.H{"error": "Records of this model can't be deleted", "modelName": mConfig.ModelName})
+			return nil, "", false
+		}
+		return func(tx *gorm.DB, id int64) ([]model.FieldChange, error) {
+			return s.deleteModelRecord(ctx, tx, mConfig, id)
+		}, "delete", true
+
+	case bulkActionEnable, bulkActionDisable, bulkActionSetField:
+		field, value := bulkSwitchField, interface{}(1)
+		if action == bulkActionDisable {
+			value = 0
+		} else if action == bulkActionSetField {
+			field, _ = payload["field"].(string)
+			value = payload["value"]
+		}
+
+		if !slices.Contains(mConfig.EditableFields, field) || mConfig.FieldConfig[field].IsPassword {
+			ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("Field '%s' can't be set in bulk", field)})
+			return nil, "", false
+		}
+		if s.Config.AccessCheckFunc(ctx, mConfig.ModelName, field, "update") != true {
+			ctx.JSON(http.StatusForbidden, model.H{"error": fmt.Sprintf("Access denied to update field '%s'", field)})
+			return nil, "", false
+		}
+
+		updateData := map[string]interface{}{field: value}
+		if !s.validateFieldValueType(ctx, mConfig, updateData) {
+			return nil, "", false
+		}
+		var versionColumn recordVersionColumn
+		if mConfig.VersionField != "" {
+			versionColumn = s.takeRecordVersionColumn(mConfig)
+		}
+		return func(tx *gorm.DB, id int64) ([]model.FieldChange, error) {
+			return s.updateRecordField(ctx, tx, mConfig, versionColumn, id, field, updateData[field])
+		}, "update", true
+
+	default:
+		bulkAction, exists := s.Config.BulkActions[action]
+		if !exists || bulkAction.Apply == nil {
+			ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("Unknown bulk action '%s'", action)})
+			return nil, "", false
+		}
+		return func(tx *gorm.DB, id int64) ([]model.FieldChange, error) {
+			if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(notSoftDeleted(mConfig)).Take(&map[string]interface{}{}).Error; err != nil {
+				return nil, newHttpError(http.StatusNotFound, "Record not found")
+			}
+			if err := bulkAction.Apply(ctx, tx, mConfig.DbTable, id); err != nil {
+				return nil, newHttpError(http.StatusBadRequest, err.Error())
+			}
+			return nil, nil
+		}, action, true
+	}
+}
+
+// updateRecordField sets the field of the record inside the transaction with BeforeUpdate and AfterUpdate hooks,
+// the version of "versionField" is incremented without the check, the bulk action isn't based on a seen version
+func (s *Service) updateRecordField(ctx *model.Request, tx *gorm.DB, mConfig *model.ConfigOfModel, versionColumn recordVersionColumn, id int64, field string, value interface{}) ([]model.FieldChange, error) {
+	originalData := make(map[string]interface{})
+	if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(notSoftDeleted(mConfig)).Select(field).Take(&originalData).Error; err != nil {
+		return nil, newHttpError(http.StatusNotFound, "Record not found")
+	}
+
+	if dateTimeFieldConfig, dateTimeFieldExists := mConfig.DateTimeFields[field]; dateTimeFieldExists {
+		originalData[field] = sqlutils.ExtractFormattedTime(originalData[field], dateTimeFieldConfig)
+	}
+	if fmt.Sprint(originalData[field]) == fmt.Sprint(value) {
+		return nil, nil
+	}
+
+	if s.Config.BeforeUpdate != nil {
+		if err := s.Config.BeforeUpdate(ctx, tx, mConfig.DbTable, id, field); err != nil {
+			return nil, newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	updateData := map[string]interface{}{field: value}
+	updateValues := map[string]interface{}{field: value}
+	if mConfig.VersionField != "" {
+		updateValues[mConfig.VersionField] = versionColumn.nextRecordVersion()
+	}
+	if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Updates(updateValues).Error; err != nil {
+		log.Printf("Wedyta: Failed to update model, error: %v", err)
+		return nil, newHttpError(http.StatusInternalServerError, "Failed to update model")
+	}
+
+	changes := auditChanges(mConfig, originalData, updateData)
+	if err := s.writeAudit(ctx, tx, mConfig, id, "update", changes); err != nil {
+		return nil, err
+	}
+
+	if s.Config.AfterUpdate != nil {
+		if err := s.Config.AfterUpdate(ctx, tx, mConfig.DbTable, id, field, fmt.Sprintf("%v", originalData[field]), fmt.Sprintf("%v", value)); err != nil {
+			return nil, newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return changes, nil
+}
+
+// bulkActionsData returns the bulk actions of the model permitted to the user, with the fields permitted for setField
+func (s *Service) bulkActionsData(ctx *model.Request, mConfig *model.ConfigOfModel, cache *model.RenderTableCache) ([]bulkActionData, []headerData) {
+	var actions []bulkActionData
+	var fields []headerData
+	for _, action := range mConfig.BulkActions {
+		if !s.fieldPermitted(ctx, mConfig, "", bulkAccessAction(action), cache) {
+			continue
+		}
+
+		title, builtin := builtinBulkActionTitles[action]
+		switch {
+		case action == bulkActionDelete && !mConfig.Deletable:
+			continue
+		case action == bulkActionEnable || action == bulkActionDisable:
+			if !slices.Contains(mConfig.EditableFields, bulkSwitchField) || !s.fieldPermitted(ctx, mConfig, bulkSwitchField, "update", cache) {
+				continue
+			}
+		case action == bulkActionSetField:
+			for _, field := range mConfig.EditableFields {
+				if mConfig.FieldConfig[field].IsPassword || !s.fieldPermitted(ctx, mConfig, field, "update", cache) {
+					continue
+				}
+				fields = append(fields, headerData{Field: field, Header: template.HTML(mConfig.FieldConfig[field].Header)})
+			}
+			if len(fields) == 0 {
+				continue
+			}
+		case !builtin:
+			bulkAction, exists := s.Config.BulkActions[action]
+			if !exists {
+				continue
+			}
+			title = bulkAction.Title
+			if title == "" {
+				title = action
+			}
+		}
+
+		actions = append(actions, bulkActionData{Name: action, Title: s.translate(mConfig, title)})
+	}
+	return actions, fields
+}
+
+// getIdsFromPayload parses "ids" of the bulk request, the ids may be numbers or strings
+func getIdsFromPayload(payload map[string]interface{}) ([]int64, error) {
+	values, ok := payload["ids"].([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, errors.New("IDs are required")
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, value := range values {
+		id, err := getIdFromPayload(map[string]interface{}{"id": value})
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+func TestBulkActions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tasks.json": {Data: []byte(`{"fields": ["id", "name", "is_active", "priority"], "editableFields": ["name", "is_active", "priority"], "deletable": true,
+			"bulkActions": ["delete", "enable", "disable", "setField", "archive"]}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "tasks", "fields": ["id", "name"], "bulkActions": ["delete", "enable", "unknown"]}`)},
+		"granted.json": {Data: []byte(`{"dbTable": "tasks", "fields": ["id", "name"], "bulkActions": ["archive"],
+			"permissions": {"*": {"actions": ["read", "archive", "bogus"]}}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE tasks (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, is_active INTEGER, priority INTEGER);
+		INSERT INTO tasks (name, is_active, priority) VALUES ('write', 1, 1), ('test', 1, 1), ('ship', 0, 1)`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	s.Config.BulkActions = map[string]model.BulkAction{
+		"archive": {Title: "Archive", Apply: func(ctx *model.Request, db *gorm.DB, table string, id int64) error {
+			if err := db.Table(table).Where("id = ?", id).Update("name", "archived").Error; err != nil {
+				return err
+			}
+			if id == 2 {
+				return errors.New("task is in progress")
+			}
+			return nil
+		}},
+	}
+	deniedAction := ""
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return action != deniedAction
+	}
+
+	bulk := func(payload string) (int, map[string]interface{}) {
+		ctx := model.NewRequest(httptest.NewRequest("POST", "/wedyta/bulk", strings.NewReader(payload)), nil)
+		s.Bulk(ctx)
+		var result map[string]interface{}
+		if err := json.Unmarshal(ctx.Response.Body, &result); err != nil {
+			t.Fatalf("can't parse the response %s: %v", ctx.Response.Body, err)
+		}
+		return ctx.Response.Status, result
+	}
+	column := func(field string) string {
+		var values []string
+		if err := s.DB.Table("tasks").Order("id").Pluck(field, &values).Error; err != nil {
+			t.Fatalf("failed to select %s: %v", field, err)
+		}
+		return strings.Join(values, ",")
+	}
+
+	status, result := bulk(`{"modelName": "tasks", "action": "disable", "ids": [1, "2", 99]}`)
+	if status != http.StatusOK || column("is_active") != "0,0,0" || len(result["succeeded"].([]interface{})) != 2 {
+		t.Errorf("unexpected disable result %d %v, is_active: %s", status, result, column("is_active"))
+	}
+	if failed := result["failed"].([]interface{}); len(failed) != 1 || failed[0].(map[string]interface{})["error"] != "Record not found" {
+		t.Errorf("expected the failure of the missing record, got %v", failed)
+	}
+
+	if status, _ := bulk(`{"modelName": "tasks", "action": "setField", "field": "priority", "value": "high", "ids": [1]}`); status != http.StatusBadRequest {
+		t.Errorf("expected the invalid value to be rejected, got %d", status)
+	}
+	if status, _ := bulk(`{"modelName": "tasks", "action": "setField", "field": "priority", "value": "5", "ids": [1, 3]}`); status != http.StatusOK || column("priority") != "5,1,5" {
+		t.Errorf("unexpected setField result %d, priority: %s", status, column("priority"))
+	}
+
+	// the failed record is rolled back to its savepoint, the others are committed
+	status, result = bulk(`{"modelName": "tasks", "action": "archive", "ids": [1, 2, 3]}`)
+	if status != http.StatusOK || result["success"] != false || column("name") != "archived,test,archived" {
+		t.Errorf("unexpected archive result %d %v, names: %s", status, result, column("name"))
+	}
+
+	deniedAction = "delete"
+	if status, _ := bulk(`{"modelName": "tasks", "action": "delete", "ids": [1]}`); status != http.StatusForbidden {
+		t.Errorf("expected the denied delete, got %d", status)
+	}
+	ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/tasks", nil), map[string]string{"modelName": "tasks"})
+	s.RenderTable(ctx)
+	table := string(ctx.Response.Body)
+	for _, expected := range []string{`class="wedyta-bulk-form`, `<option value="archive">Archive</option>`, `<option value="setField">Set field</option>`, `class="form-check-input wedyta-bulk-select" type="checkbox" value="3"`} {
+		if !strings.Contains(table, expected) {
+			t.Errorf("expected %q in\n%s", expected, table)
+		}
+	}
+	if strings.Contains(table, `<option value="delete">`) {
+		t.Errorf("unexpected denied delete action in\n%s", table)
+	}
+
+	deniedAction = ""
+	if status, _ := bulk(`{"modelName": "tasks", "action": "delete", "ids": [2]}`); status != http.StatusOK || column("id") != "1,3" {
+		t.Errorf("unexpected delete result %d, ids: %s", status, column("id"))
+	}
+
+	issues := s.ValidateModel("broken")
+	if len(issues) != 3 || issues[0].Path != "bulkActions.0" || issues[1].Path != "bulkActions.1" || issues[2].Path != "bulkActions.2" {
+		t.Errorf("expected the issues of the bulk actions, got:\n%v", issues.Error())
+	}
+
+	issues = s.ValidateModel("granted")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `"bogus"`) {
+		t.Errorf("expected the custom bulk action to be a known permission, got:\n%v", issues.Error())
+	}
+}
@@ -6,20 +6,22 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/gin-gonic/gin"
+	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
 )
 
-func (s *Service) HandleTableCreateRecord(ctx *gin.Context) {
+func (s *Service) HandleTableCreateRecord(ctx *model.Request) {
 	var payload map[string]interface{}
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Invalid JSON"})
 		return
 	}
 
 	modelName, ok := payload["modelName"].(string)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Model name is required"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Model name is required"})
 		return
 	}
 
@@ -43,7 +45,7 @@ func (s *Service) HandleTableCreateRecord(ctx *gin.Context) {
 	// check RequiredFields
 	for _, requiredField := range mConfig.RequiredFields {
 		if value, exists := payload[requiredField]; !exists || value == "" {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ValueField '%s' is required", requiredField)})
+			ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("ValueField '%s' is required", requiredField)})
 			return
 		}
 	}
@@ -52,51 +54,88 @@ func (s *Service) HandleTableCreateRecord(ctx *gin.Context) {
 	for _, noZeroField := range mConfig.NoZeroValueFields {
 		if value, exists := payload[noZeroField]; exists {
 			if number, ok := value.(float64); ok && number == 0 {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ValueField '%s' cannot be zero", noZeroField)})
+				ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("ValueField '%s' cannot be zero", noZeroField)})
 				return
 			}
 		}
 	}
 
 	if len(insertData) == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No data to insert"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "No data to insert"})
 		return
 	}
 
+	for field := range insertData {
+		if field == mConfig.Parent.LocalConnectionField {
+			continue
+		}
+		if s.Config.AccessCheckFunc(ctx, modelName, field, action) != true {
+			ctx.JSON(http.StatusForbidden, model.H{"error": fmt.Sprintf("Access denied to fill field '%s'", field)})
+			return
+		}
+	}
+
 	//fixCheckboxValue(insertData)
 
 	if !s.validateFieldValueType(ctx, mConfig, insertData) {
 		return
 	}
 
-	if s.Config.BeforeCreate != nil {
-		permitCreate, msg := s.Config.BeforeCreate(ctx, s.DB, mConfig.DbTable, insertData)
-		if !permitCreate {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": msg})
-			return
+	var insertedID int64
+	var changes []model.FieldChange
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if s.Config.BeforeCreate != nil {
+			permitCreate, msg := s.Config.BeforeCreate(ctx, tx, mConfig.DbTable, insertData)
+			if !permitCreate {
+				return newHttpError(http.StatusBadRequest, msg)
+			}
 		}
-	}
 
-	for field, val := range insertData {
-		fldCfg := mConfig.FieldConfig[field]
-		if fldCfg.IsPassword {
-			encryptedPassword, err := s.Config.EncryptPlainPasswordFunc(ctx, mConfig.DbTable, field, insertData, val.(string))
-			if err != nil {
-				log.Printf("HandleTableCreateRecord: Error encrypting password for field '%s': %v", field, err)
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		for field, val := range insertData {
+			fldCfg := mConfig.FieldConfig[field]
+			if fldCfg.IsPassword {
+				encryptedPassword, err := s.Config.EncryptPlainPasswordFunc(ctx, mConfig.DbTable, field, insertData, val.(string))
+				if err != nil {
+					log.Printf("HandleTableCreateRecord: Error encrypting password for field '%s': %v", field, err)
+					return newHttpError(http.StatusInternalServerError, "Internal Server Error")
+				}
+				insertData[field] = encryptedPassword
 			}
-			insertData[field] = encryptedPassword
 		}
-	}
 
-	var insertedID int64
-	if err := s.DB.Table(mConfig.DbTable).Create(insertData).Error; err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data"})
+		// taken before Create, which adds the primary key to insertData
+		changes = auditChanges(mConfig, nil, insertData)
+
+		if err := tx.Table(mConfig.DbTable).Create(insertData).Error; err != nil {
+			log.Printf("HandleTableCreateRecord: Failed to insert data: %v", err)
+			return newHttpError(http.StatusInternalServerError, "Failed to insert data")
+		}
+
+		var err error
+		insertedID, err = sqlutils.LastInsertID(tx)
+		if err != nil {
+			log.Printf("HandleTableCreateRecord: can't take inserted id: %v", err)
+		}
+
+		if err := s.writeAudit(ctx, tx, mConfig, insertedID, "create", changes); err != nil {
+			return err
+		}
+
+		if s.Config.AfterCreate != nil {
+			if err := s.Config.AfterCreate(ctx, tx, mConfig.DbTable, insertedID); err != nil {
+				return newHttpError(http.StatusBadRequest, err.Error())
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
 		return
-	} else {
-		s.DB.Raw("SELECT LAST_INSERT_ID()").Scan(&insertedID)
 	}
 
+	s.fireAfterCommit(ctx, mConfig, "create", insertedID, changes)
+
 	successfullyCreatedDestination := ""
 
 	if value, exists := payload["successfullyCreatedDestination"]; exists {
@@ -106,5 +145,5 @@ func (s *Service) HandleTableCreateRecord(ctx *gin.Context) {
 		}
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"success": true, "successfullyCreatedDestination": successfullyCreatedDestination})
+	ctx.JSON(http.StatusOK, model.H{"success": true, "successfullyCreatedDestination": successfullyCreatedDestination})
 }
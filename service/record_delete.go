@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+// Delete deletes the record of a model with "deletable": true, models with "softDelete" move the record to the trash
+func (s *Service) Delete(ctx *model.Request) {
+	mConfig, id, ok := s.takeRecordActionPayload(ctx, "delete")
+	if !ok {
+		return
+	}
+
+	if !mConfig.Deletable {
+		ctx.JSON(http.StatusForbidden, model.H{"error": "Records of this model can't be deleted", "modelName": mConfig.ModelName})
+		return
+	}
+
+	var changes []model.FieldChange
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		changes, err = s.deleteModelRecord(ctx, tx, mConfig, id)
+		return err
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	s.fireAfterCommit(ctx, mConfig, "delete", id, changes)
+
+	ctx.JSON(http.StatusOK, model.H{"success": true, "message": "Record deleted successfully"})
+}
+
+// deleteModelRecord deletes the record inside the transaction, models with "softDelete" move the record to the trash
+func (s *Service) deleteModelRecord(ctx *model.Request, tx *gorm.DB, mConfig *model.ConfigOfModel, id int64) ([]model.FieldChange, error) {
+	// Retrieve the record for the audit, also checks that the record is accessible by sqlWhere
+	originalData := make(map[string]interface{})
+	if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(notSoftDeleted(mConfig)).Take(&originalData).Error; err != nil {
+		return nil, newHttpError(http.StatusNotFound, "Record not found")
+	}
+
+	if !mConfig.SoftDelete.Enabled() {
+		changes := auditChanges(mConfig, originalData, nil)
+		return changes, s.deleteRecord(ctx, tx, mConfig, id, "delete", changes)
+	}
+
+	if s.Config.BeforeDelete != nil {
+		if err := s.Config.BeforeDelete(ctx, tx, mConfig.DbTable, id); err != nil {
+			return nil, newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	changes, err := s.softDeleteRecord(ctx, tx, mConfig, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Config.AfterDelete != nil {
+		if err := s.Config.AfterDelete(ctx, tx, mConfig.DbTable, id); err != nil {
+			return nil, newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return changes, nil
+}
+
+// deleteRecord removes the row inside the transaction with BeforeDelete and AfterDelete hooks
+func (s *Service) deleteRecord(ctx *model.Request, tx *gorm.DB, mConfig *model.ConfigOfModel, id int64, action string, changes []model.FieldChange) error {
+	if s.Config.BeforeDelete != nil {
+		if err := s.Config.BeforeDelete(ctx, tx, mConfig.DbTable, id); err != nil {
+			return newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Delete(map[string]interface{}{}).Error; err != nil {
+		log.Printf("Wedyta: Failed to delete record, error: %v", err)
+		return newHttpError(http.StatusInternalServerError, "Failed to delete record")
+	}
+
+	if err := s.writeAudit(ctx, tx, mConfig, id, action, changes); err != nil {
+		return err
+	}
+
+	if s.Config.AfterDelete != nil {
+		if err := s.Config.AfterDelete(ctx, tx, mConfig.DbTable, id); err != nil {
+			return newHttpError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// takeRecordActionPayload parses {"modelName": ..., "id": ...} of the record actions and checks the access
+func (s *Service) takeRecordActionPayload(ctx *model.Request, action string) (*model.ConfigOfModel, int64, bool) {
+	var payload map[string]interface{}
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Invalid JSON"})
+		return nil, 0, false
+	}
+
+	return s.recordActionTarget(ctx, payload, action)
+}
+
+// recordActionTarget loads the model config and the id of the parsed payload of the record action, checking the access
+func (s *Service) recordActionTarget(ctx *model.Request, payload map[string]interface{}, action string) (*model.ConfigOfModel, int64, bool) {
+	modelName, ok := payload["modelName"].(string)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Model name is required"})
+		return nil, 0, false
+	}
+
+	if s.Config.AccessCheckFunc(ctx, modelName, "", action) != true {
+		ctx.JSON(http.StatusForbidden, model.H{"error": "Access denied", "modelName": modelName})
+		return nil, 0, false
+	}
+
+	mConfig := s.loadModelConfig(ctx, modelName, payload)
+	if mConfig == nil {
+		return nil, 0, false
+	}
+
+	id, err := getIdFromPayload(payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": err.Error()})
+		return nil, 0, false
+	}
+
+	return mConfig, id, true
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+)
+
+// duplicateQueryParam is the query parameter of the create page giving the record to prefill the form from
+const duplicateQueryParam = "duplicate"
+
+// duplicateURL returns the URL of the create page prefilled from the record
+func duplicateURL(mConfig *model.ConfigOfModel, pkValue string) string {
+	separator := "?"
+	if strings.HasPrefix(mConfig.AdditionalUrlParams, "?") {
+		separator = "&"
+	}
+	return "/wedyta/" + mConfig.ModelName + "/create" + mConfig.AdditionalUrlParams + separator + duplicateQueryParam + "=" + pkValue
+}
+
+// duplicateValues returns the values of the addable fields of the record for prefilling the add form,
+// the primary key, the unique columns, the passwords and duplicate.resetFields are left out.
+// The values are only a draft of the new record, it's created by the usual request, so BeforeCreate applies.
+func (s *Service) duplicateValues(ctx *model.Request, mConfig *model.ConfigOfModel, recID string) (map[string]string, error) {
+	if !mConfig.Duplicate.Enabled {
+		return nil, fmt.Errorf("duplicate is not enabled for model %s", mConfig.ModelName)
+	}
+	if !s.Config.AccessCheckFunc(ctx, mConfig.ModelName, "", "read") {
+		return nil, ErrAccessDenied
+	}
+
+	var record map[string]interface{}
+	if err := s.DB.
+		Table(mConfig.DbTable).
+		Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), recID).
+		Where(mConfig.SqlWhere).
+		Scopes(notSoftDeleted(mConfig)).
+		Take(&record).Error; err != nil {
+		return nil, err
+	}
+
+	uniqueColumns, err := sqlutils.GetUniqueColumns(s.DB, mConfig.DbTable)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, field := range mConfig.AddableFields {
+		fldCfg := mConfig.FieldConfig[field]
+		if field == mConfig.DbTablePrimaryKey || fldCfg.IsPassword || slices.Contains(uniqueColumns, field) || slices.Contains(mConfig.Duplicate.ResetFields, field) {
+			continue
+		}
+
+		value, exists := record[field]
+		if !exists || value == nil || !s.Config.AccessCheckFunc(ctx, mConfig.ModelName, field, "read") {
+			continue
+		}
+
+		if dateTimeFieldConfig, dateTimeFieldExists := mConfig.DateTimeFields[field]; dateTimeFieldExists {
+			values[field] = sqlutils.ExtractFormattedTime(value, dateTimeFieldConfig)
+		} else if bytes, isBytes := value.([]byte); isBytes {
+			values[field] = string(bytes)
+		} else {
+			values[field] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	return values, nil
+}
+
+// duplicatePrefill returns the values for the add form of the create page if it's opened by the duplicate action
+func (s *Service) duplicatePrefill(ctx *model.Request, mConfig *model.ConfigOfModel) (map[string]string, error) {
+	recID, exists := ctx.GetQuery(duplicateQueryParam)
+	if !exists || recID == "" {
+		return nil, nil
+	}
+
+	values, err := s.duplicateValues(ctx, mConfig, recID)
+	if errors.Is(err, ErrAccessDenied) {
+		log.Printf("WeDyTa: duplicating %s #%s is not permitted", mConfig.ModelName, recID)
+		return nil, nil
+	}
+	return values, err
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestDuplicateRecord(t *testing.T) {
+	fsys := fstest.MapFS{
+		"products.json": {Data: []byte(`{"fields": ["id", "sku", "name", "secret", "note", "color", "controls"], "addableFields": ["sku", "name", "secret", "note", "color"],
+			"columnDataFunc": {"controls": "stdRecordControls"}, "password": {"secret": {}}, "duplicate": {"resetFields": ["note"]}}`)},
+		"plainProducts.json": {Data: []byte(`{"dbTable": "products", "fields": ["id", "name", "controls"], "addableFields": ["name"], "columnDataFunc": {"controls": "stdRecordControls"}}`)},
+		"broken.json":        {Data: []byte(`{"dbTable": "products", "fields": ["id", "name"], "duplicate": {"resetFields": ["missing"]}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE products (id INTEGER PRIMARY KEY AUTOINCREMENT, sku TEXT UNIQUE, name TEXT, secret TEXT, note TEXT, color TEXT);
+		INSERT INTO products (sku, name, secret, note, color) VALUES ('A-1', 'lamp', 's3cret', 'sold out', 'red')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	render := func(url, modelName string, handler func(ctx *model.Request)) string {
+		ctx := model.NewRequest(httptest.NewRequest("GET", url, nil), map[string]string{"modelName": modelName})
+		handler(ctx)
+		return string(ctx.Response.Body)
+	}
+
+	table := render("/wedyta/products", "products", s.RenderTable)
+	if !strings.Contains(table, `href="/wedyta/products/create?duplicate=1" title="Duplicate"`) {
+		t.Errorf("expected the duplicate action in\n%s", table)
+	}
+	if plain := render("/wedyta/plainProducts", "plainProducts", s.RenderTable); strings.Contains(plain, "record-control-duplicate") {
+		t.Errorf("unexpected duplicate action without the config in\n%s", plain)
+	}
+
+	form := render("/wedyta/products/create?duplicate=1&color=blue", "products", s.RenderTableRecordCreate)
+	for _, expected := range []string{`>lamp</textarea>`, `>blue</textarea>`} {
+		if !strings.Contains(form, expected) {
+			t.Errorf("expected %q in\n%s", expected, form)
+		}
+	}
+	for _, unexpected := range []string{"A-1", "s3cret", "sold out", "red"} {
+		if strings.Contains(form, unexpected) {
+			t.Errorf("unexpected %q in\n%s", unexpected, form)
+		}
+	}
+
+	issues := s.ValidateModel("broken")
+	if len(issues) != 1 || issues[0].Path != "duplicate.resetFields.missing" {
+		t.Errorf("expected the issue of the reset field, got:\n%v", issues.Error())
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+// recordNavigation returns the URLs of the previous and the next records of the table, empty if there is none
+func (s *Service) recordNavigation(mConfig *model.ConfigOfModel, recID int64, filters map[string]interface{}) (prevURL, nextURL string) {
+	prevID, nextID, err := s.adjacentRecords(mConfig, recID, filters)
+	if err != nil {
+		log.Printf("WeDyTa: can't find the adjacent records of %s #%d: %v", mConfig.ModelName, recID, err)
+		return "", ""
+	}
+
+	if prevID != nil {
+		prevURL = fmt.Sprintf("/wedyta/%s/%d%s", mConfig.ModelName, *prevID, mConfig.AdditionalUrlParams)
+	}
+	if nextID != nil {
+		nextURL = fmt.Sprintf("/wedyta/%s/%d%s", mConfig.ModelName, *nextID, mConfig.AdditionalUrlParams)
+	}
+	return prevURL, nextURL
+}
+
+// adjacentRecords finds the records around the record in the order of the table: orderBy, then the primary key.
+// The records are limited like the table by sqlWhere, the soft deletion and the filters, and by the parent record.
+func (s *Service) adjacentRecords(mConfig *model.ConfigOfModel, recID int64, filters map[string]interface{}) (prevID, nextID *int64, err error) {
+	columns := navigationColumns(mConfig)
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	current := map[string]interface{}{}
+	if err := s.DB.Table(mConfig.DbTable).Select(names).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), recID).Take(&current).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, name := range names {
+		// NULL isn't comparable, the record is placed by the primary key
+		if current[name] == nil {
+			columns = []orderColumn{{Name: mConfig.DbTablePrimaryKey}}
+			current = map[string]interface{}{mConfig.DbTablePrimaryKey: recID}
+			break
+		}
+	}
+
+	if prevID, err = s.adjacentRecord(mConfig, columns, current, filters, true); err != nil {
+		return nil, nil, err
+	}
+	if nextID, err = s.adjacentRecord(mConfig, columns, current, filters, false); err != nil {
+		return nil, nil, err
+	}
+	return prevID, nextID, nil
+}
+
+// adjacentRecord takes the first record before or after the current values of the columns
+func (s *Service) adjacentRecord(mConfig *model.ConfigOfModel, columns []orderColumn, current map[string]interface{}, filters map[string]interface{}, before bool) (*int64, error) {
+	// (a, b) > (1, 2) is a > 1 OR a = 1 AND b > 2, the direction of every column is taken into account
+	var conditions, order []string
+	var args []interface{}
+	for i, column := range columns {
+		var condition []string
+		for _, equal := range columns[:i] {
+			condition = append(condition, equal.Name+" = ?")
+			args = append(args, current[equal.Name])
+		}
+		greater := column.Desc == before
+		if greater {
+			condition = append(condition, column.Name+" > ?")
+		} else {
+			condition = append(condition, column.Name+" < ?")
+		}
+		args = append(args, current[column.Name])
+		conditions = append(conditions, "("+strings.Join(condition, " AND ")+")")
+
+		if greater {
+			order = append(order, column.Name+" ASC")
+		} else {
+			order = append(order, column.Name+" DESC")
+		}
+	}
+
+	var ids []int64
+	if err := s.DB.
+		Table(mConfig.DbTable).
+		Where(mConfig.SqlWhere).
+		Scopes(notSoftDeleted(mConfig), filtered(filters), ofParentRecord(mConfig)).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order(strings.Join(order, ", ")).
+		Limit(1).
+		Pluck(mConfig.DbTablePrimaryKey, &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids[0], nil
+}
+
+type orderColumn struct {
+	Name string
+	Desc bool
+}
+
+var orderColumnRe = regexp.MustCompile(`(?i)^([a-z_][a-z0-9_]*)(?:\s+(asc|desc))?$`)
+
+// navigationColumns are the columns of orderBy followed by the primary key,
+// only the primary key if orderBy isn't a list of columns, e.g. it has expressions
+func navigationColumns(mConfig *model.ConfigOfModel) []orderColumn {
+	pk := mConfig.DbTablePrimaryKey
+	var columns []orderColumn
+	if mConfig.OrderBy != "" {
+		for _, part := range strings.Split(mConfig.OrderBy, ",") {
+			match := orderColumnRe.FindStringSubmatch(strings.TrimSpace(part))
+			if match == nil {
+				return []orderColumn{{Name: pk}}
+			}
+			if match[1] == pk {
+				// the order is already unique, the columns after the primary key don't matter
+				return append(columns, orderColumn{Name: pk, Desc: strings.EqualFold(match[2], "desc")})
+			}
+			columns = append(columns, orderColumn{Name: match[1], Desc: strings.EqualFold(match[2], "desc")})
+		}
+	}
+	return append(columns, orderColumn{Name: pk})
+}
+
+// ofParentRecord is a gorm scope which selects the children of the parent record given by the query variable of "parent"
+func ofParentRecord(mConfig *model.ConfigOfModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if mConfig.Parent.LocalConnectionField == "" || mConfig.Parent.QueryVariableValue == "" {
+			return db
+		}
+		return db.Where(fmt.Sprintf("%s = ?", mConfig.Parent.LocalConnectionField), mConfig.Parent.QueryVariableValue)
+	}
+}
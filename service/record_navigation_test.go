@@ -0,0 +1,58 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestRecordNavigation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"owners.json":   {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"], "breadcrumb": {"labelField": "owner_id"}}`)},
+		"byLength.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"], "orderBy": "length(name) DESC", "breadcrumb": {"labelField": "owner_id"}}`)},
+		"items.json": {Data: []byte(`{"fields": ["id", "name", "owner_id"], "orderBy": "name DESC", "sqlWhere": "name <> 'hidden'", "breadcrumb": {"labelField": "name"},
+			"parent": {"modelName": "owners", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	// ordered by name DESC: plum (4), pear (2), hidden (5) is out of sqlWhere, fig (3), apple (1) of another owner
+	if err := s.DB.Exec(`INSERT INTO items (name, owner_id) VALUES ('apple', 2), ('pear', 1), ('fig', 1), ('plum', 1), ('hidden', 1)`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	render := func(url, recID string) string {
+		modelName := strings.Split(url, "/")[2]
+		ctx := model.NewRequest(httptest.NewRequest("GET", url, nil), map[string]string{"modelName": modelName, "recID": recID})
+		s.RenderTableRecord(ctx)
+		return string(ctx.Response.Body)
+	}
+
+	tests := []struct {
+		url, recID string
+		expected   []string
+		unexpected []string
+	}{
+		{"/wedyta/items/2?owner=1", "2", []string{`href="/wedyta/items/4?owner=1" rel="prev"`, `href="/wedyta/items/3?owner=1" rel="next"`, `aria-current="page">pear &nbsp;`}, []string{"#2"}},
+		{"/wedyta/items/3?owner=1", "3", []string{`href="/wedyta/items/2?owner=1" rel="prev"`, `aria-disabled="true">Next`}, nil},
+		{"/wedyta/items/4?owner=1", "4", []string{`aria-disabled="true"><i class="bi-chevron-left"></i> Previous`, `href="/wedyta/items/2?owner=1" rel="next"`}, nil},
+		{"/wedyta/items/3", "3", []string{`href="/wedyta/items/2?owner=" rel="prev"`, `href="/wedyta/items/1?owner=" rel="next"`}, nil},
+		{"/wedyta/items/3?owner=1", "3", []string{`href="/wedyta/owners/1">2</a>`}, []string{"%!s"}},
+		// the expression of orderBy isn't a column list, the records are taken in the order of the primary key
+		{"/wedyta/byLength/2", "2", []string{`href="/wedyta/byLength/1" rel="prev"`, `href="/wedyta/byLength/3" rel="next"`, `aria-current="page">1 &nbsp;`}, []string{"%!s"}},
+	}
+	for _, test := range tests {
+		body := render(test.url, test.recID)
+		for _, expected := range test.expected {
+			if !strings.Contains(body, expected) {
+				t.Errorf("%s: expected %q in\n%s", test.url, expected, body)
+			}
+		}
+		for _, unexpected := range test.unexpected {
+			if strings.Contains(body, unexpected) {
+				t.Errorf("%s: unexpected %q", test.url, unexpected)
+			}
+		}
+	}
+}
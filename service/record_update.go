@@ -1,32 +1,35 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 
-	"github.com/gin-gonic/gin"
+	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils"
 	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
 )
 
 // Update updates the fields of a specified model based on the allowedFields
-func (s *Service) Update(ctx *gin.Context) {
+func (s *Service) Update(ctx *model.Request) {
 	var payload map[string]interface{}
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Invalid JSON"})
 		return
 	}
 
 	modelName, ok := payload["modelName"].(string)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Model name is required"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Model name is required"})
 		return
 	}
 
 	if s.Config.AccessCheckFunc(ctx, modelName, "", "update") != true {
 		//ctx.String(http.StatusForbidden, "Forbidden RenderTable: "+modelName)
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "modelName": "$modelName"})
+		ctx.JSON(http.StatusForbidden, model.H{"error": "Access denied", "modelName": "$modelName"})
 		return
 	}
 
@@ -35,14 +38,23 @@ func (s *Service) Update(ctx *gin.Context) {
 		return
 	}
 
-	fmt.Printf("payload=%v\n", payload)
-
 	id, err := getIdFromPayload(payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": err.Error()})
 		return
 	}
-	fmt.Printf("id=%d\n", id)
+
+	// optimistic concurrency control
+	var version interface{}
+	var versionColumn recordVersionColumn
+	if mConfig.VersionField != "" {
+		versionColumn = s.takeRecordVersionColumn(mConfig)
+		version, err = versionColumn.takeRecordVersionFromPayload(payload)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, model.H{"error": err.Error()})
+			return
+		}
+	}
 
 	var allowed []string
 
@@ -63,22 +75,27 @@ func (s *Service) Update(ctx *gin.Context) {
 	}
 
 	if len(updateData) == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "No valid fields to update"})
 		return
 	}
 
+	for field := range updateData {
+		if s.Config.AccessCheckFunc(ctx, modelName, field, "update") != true {
+			ctx.JSON(http.StatusForbidden, model.H{"error": fmt.Sprintf("Access denied to update field '%s'", field)})
+			return
+		}
+	}
+
 	//fixCheckboxValue(updateData)
 
 	if !s.validateFieldValueType(ctx, mConfig, updateData) {
 		return
 	}
 
-	fmt.Printf("updateData=%v\n", updateData)
-
 	// Retrieve original values for fields to be updated
 	originalData := make(map[string]interface{})
-	if err := s.DB.Table(mConfig.DbTable).Where("id = ?", id).Select(allowed).Take(&originalData).Error; err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve original data"})
+	if err := s.DB.Table(mConfig.DbTable).Where("id = ?", id).Scopes(notSoftDeleted(mConfig)).Select(allowed).Take(&originalData).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.H{"error": "Failed to retrieve original data"})
 		return
 	}
 
@@ -101,41 +118,91 @@ func (s *Service) Update(ctx *gin.Context) {
 	}
 
 	if len(updateData) == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No new data for update"})
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "No new data for update"})
 		return
 	}
 
-	for field, val := range updateData {
-		fldCfg := mConfig.FieldConfig[field]
-		if fldCfg.IsPassword {
-			encryptedPassword, err := s.Config.EncryptPlainPasswordFunc(ctx, mConfig.DbTable, field, updateData, val.(string))
+	var changes []model.FieldChange
+	var newVersion string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		for field, val := range updateData {
+			fldCfg := mConfig.FieldConfig[field]
+			if fldCfg.IsPassword {
+				encryptedPassword, err := s.Config.EncryptPlainPasswordFunc(ctx, mConfig.DbTable, field, updateData, val.(string))
+				if err != nil {
+					log.Printf("Update: Error encrypting password for field '%s': %v", field, err)
+					return newHttpError(http.StatusInternalServerError, "Internal Server Error")
+				}
+				updateData[field] = encryptedPassword
+			}
+		}
+
+		if s.Config.BeforeUpdate != nil {
+			for field := range updateData {
+				if err := s.Config.BeforeUpdate(ctx, tx, mConfig.DbTable, id, field); err != nil {
+					return newHttpError(http.StatusBadRequest, err.Error())
+				}
+			}
+		}
+
+		query := tx.Table(mConfig.DbTable).Where(fmt.Sprint("id = ", id))
+		updateValues := updateData
+		if mConfig.VersionField != "" {
+			query = versionColumn.where(query, version)
+			updateValues = maps.Clone(updateData)
+			updateValues[mConfig.VersionField] = versionColumn.nextRecordVersion()
+		}
+
+		result := query.Updates(updateValues)
+		if result.Error != nil {
+			log.Printf("Wedyta: Failed to update model, error: %v", result.Error)
+			return newHttpError(http.StatusInternalServerError, "Failed to update model")
+		}
+
+		if mConfig.VersionField != "" {
+			if result.RowsAffected == 0 {
+				return errVersionConflict
+			}
+
+			newVersion, err = s.takeRecordVersion(tx, mConfig, id)
 			if err != nil {
-				log.Printf("HandleTableCreateRecord: Error encrypting password for field '%s': %v", field, err)
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+				return err
 			}
-			updateData[field] = encryptedPassword
 		}
-	}
 
-	if err := s.DB.Table(mConfig.DbTable).Where(fmt.Sprint("id = ", id)).Updates(updateData).Error; err != nil {
-		log.Printf("Wedyta: Failed to update model, error: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update model"})
-		return
-	}
+		changes = auditChanges(mConfig, originalData, updateData)
+		if err := s.writeAudit(ctx, tx, mConfig, id, "update", changes); err != nil {
+			return err
+		}
 
-	if s.Config.AfterUpdate != nil {
-		for field, newValue := range updateData {
-			originalValue, exists := originalData[field]
-			if exists && originalValue != newValue {
-				go s.Config.AfterUpdate(ctx, s.DB, mConfig.DbTable, int64(id), field, fmt.Sprintf("%v", originalValue), fmt.Sprintf("%v", newValue))
+		if s.Config.AfterUpdate != nil {
+			for field, newValue := range updateData {
+				originalValue, exists := originalData[field]
+				if exists && originalValue != newValue {
+					if err := s.Config.AfterUpdate(ctx, tx, mConfig.DbTable, id, field, fmt.Sprintf("%v", originalValue), fmt.Sprintf("%v", newValue)); err != nil {
+						return newHttpError(http.StatusBadRequest, err.Error())
+					}
+				}
 			}
 		}
+
+		return nil
+	})
+	if errors.Is(err, errVersionConflict) {
+		s.respondVersionConflict(ctx, mConfig, id, updateData)
+		return
 	}
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	s.fireAfterCommit(ctx, mConfig, "update", id, changes)
 
 	// client side js tests:
 	//time.Sleep(1000 * time.Millisecond)
-	//ctx.JSON(http.StatusBadRequest, gin.H{"error": "test fail"})
+	//ctx.JSON(http.StatusBadRequest, model.H{"error": "test fail"})
 	//return
 
-	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Model updated successfully"})
+	ctx.JSON(http.StatusOK, model.H{"success": true, "message": "Model updated successfully", "version": newVersion})
 }
@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils/sqlutils"
 )
@@ -58,11 +57,11 @@ func fixCheckboxValue(data map[string]interface{}) {
 	}
 }
 
-func (s *Service) validateFieldValueType(ctx *gin.Context, mConfig *model.ConfigOfModel, data map[string]interface{}) bool {
+func (s *Service) validateFieldValueType(ctx *model.Request, mConfig *model.ConfigOfModel, data map[string]interface{}) bool {
 	fieldTypes, err := sqlutils.GetTableColumnTypes(s.DB, mConfig.DbTable)
 	if err != nil {
 		log.Printf("Wedyta: getTableColumnTypes() error: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		ctx.JSON(http.StatusInternalServerError, model.H{"error": "Internal Server Error"})
 		return false
 	}
 
@@ -70,7 +69,7 @@ func (s *Service) validateFieldValueType(ctx *gin.Context, mConfig *model.Config
 		colType, ok := fieldTypes[field]
 		if !ok {
 			log.Printf("Wedyta: ValueField '%s' not found in TableColumnTypes", field)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			ctx.JSON(http.StatusInternalServerError, model.H{"error": "Internal Server Error"})
 			return false
 		}
 
@@ -79,7 +78,7 @@ func (s *Service) validateFieldValueType(ctx *gin.Context, mConfig *model.Config
 
 			cleaned, ok := sqlutils.SanitizeNumericField(val)
 			if !ok {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ValueField '%s' expects a numeric value", mConfig.FieldConfig[field].Header)})
+				ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("ValueField '%s' expects a numeric value", mConfig.FieldConfig[field].Header)})
 				return false
 			}
 
@@ -90,7 +89,7 @@ func (s *Service) validateFieldValueType(ctx *gin.Context, mConfig *model.Config
 				if strings.TrimSpace(original) == cleanedStr {
 					data[field] = strings.TrimSpace(original)
 				} else {
-					ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ValueField '%s' has invalid formatting (spaces or extra characters)", field)})
+					ctx.JSON(http.StatusBadRequest, model.H{"error": fmt.Sprintf("ValueField '%s' has invalid formatting (spaces or extra characters)", field)})
 					return false
 				}
 			}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
+)
+
+// versionPayloadKey is the name of the form field which contains the version of the record being edited
+const versionPayloadKey = "_version"
+
+var errVersionConflict = errors.New("record was changed by another user")
+
+// formatRecordVersion converts the value of versionField to the string embedded into the pages
+func formatRecordVersion(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// recordVersionColumn describes the column of versionField, it's taken once per update
+type recordVersionColumn struct {
+	name       string
+	numeric    bool
+	resolution time.Duration // the step of the timestamp stored by the column
+}
+
+func (s *Service) takeRecordVersionColumn(mConfig *model.ConfigOfModel) recordVersionColumn {
+	column := recordVersionColumn{name: mConfig.VersionField, resolution: time.Nanosecond}
+
+	schema, err := sqlutils.GetTableSchema(s.DB, mConfig.DbTable)
+	if err != nil {
+		log.Printf("Wedyta: GetTableSchema() error: %v", err)
+		return column
+	}
+	for _, col := range schema {
+		if col.Name == mConfig.VersionField {
+			column.numeric = sqlutils.IsNumericColumnType(col.Type)
+			column.resolution = sqlutils.TimeResolution(col.TimePrecision)
+		}
+	}
+
+	return column
+}
+
+// takeRecordVersionFromPayload returns the version sent by the client, ready to be used by where(),
+// the empty version is the NULL of the record which hasn't got the version yet.
+// The time returned by the driver as text is compared in the form it was rendered.
+func (c recordVersionColumn) takeRecordVersionFromPayload(payload map[string]interface{}) (interface{}, error) {
+	value, exists := payload[versionPayloadKey]
+	if !exists {
+		return nil, errors.New("Record version is required")
+	}
+	if value == nil || fmt.Sprint(value) == "" {
+		return nil, nil
+	}
+
+	if c.numeric {
+		version := sqlutils.ExtractInt64(value)
+		if version == 0 && fmt.Sprint(value) != "0" {
+			return nil, errors.New("Invalid record version")
+		}
+		return version, nil
+	}
+
+	version, err := time.Parse(time.RFC3339Nano, fmt.Sprint(value))
+	if err != nil {
+		if _, ok := sqlutils.ParseTime(fmt.Sprint(value)); ok {
+			return fmt.Sprint(value), nil
+		}
+		return nil, errors.New("Invalid record version")
+	}
+	return version.Truncate(c.resolution), nil
+}
+
+// where narrows the query to the record of the version, the timestamps are compared at the precision of the column
+func (c recordVersionColumn) where(query *gorm.DB, version interface{}) *gorm.DB {
+	switch v := version.(type) {
+	case nil:
+		return query.Where(fmt.Sprintf("%s IS NULL", c.name))
+	case time.Time:
+		return query.Where(fmt.Sprintf("%s >= ? AND %s < ?", c.name, c.name), v, v.Add(c.resolution))
+	default:
+		return query.Where(fmt.Sprintf("%s = ?", c.name), v)
+	}
+}
+
+// nextRecordVersion returns the value of versionField for the update: incremented integer or current time
+func (c recordVersionColumn) nextRecordVersion() interface{} {
+	if c.numeric {
+		return gorm.Expr(fmt.Sprintf("COALESCE(%s, 0) + 1", c.name))
+	}
+
+	return time.Now().Truncate(c.resolution)
+}
+
+func (s *Service) takeRecordVersion(db *gorm.DB, mConfig *model.ConfigOfModel, id int64) (string, error) {
+	record := make(map[string]interface{})
+	if err := db.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Select(mConfig.VersionField).Take(&record).Error; err != nil {
+		return "", err
+	}
+
+	return formatRecordVersion(record[mConfig.VersionField]), nil
+}
+
+// respondVersionConflict responds 409 with the current values of the fields, so the client can show a merge prompt
+func (s *Service) respondVersionConflict(ctx *model.Request, mConfig *model.ConfigOfModel, id int64, updateData map[string]interface{}) {
+	var fields []string
+	for field := range updateData {
+		if !mConfig.FieldConfig[field].IsPassword {
+			fields = append(fields, field)
+		}
+	}
+	fields = append(fields, mConfig.VersionField)
+
+	current := make(map[string]interface{})
+	if err := s.DB.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Select(fields).Take(&current).Error; err != nil {
+		log.Printf("Wedyta: can't take the conflicting record: %v", err)
+		ctx.JSON(http.StatusConflict, model.H{"error": "Record was changed or deleted by another user", "conflict": true})
+		return
+	}
+
+	currentValues := make(map[string]string)
+	for field, value := range current {
+		if field == mConfig.VersionField {
+			continue
+		}
+		if dateTimeFieldConfig, dateTimeFieldExists := mConfig.DateTimeFields[field]; dateTimeFieldExists {
+			currentValues[field] = sqlutils.ExtractFormattedTime(value, dateTimeFieldConfig)
+		} else if value != nil {
+			currentValues[field] = fmt.Sprint(value)
+		} else {
+			currentValues[field] = ""
+		}
+	}
+
+	headers := make(map[string]string)
+	for field := range currentValues {
+		headers[field] = mConfig.FieldConfig[field].Header
+	}
+
+	ctx.JSON(http.StatusConflict, model.H{
+		"error":    "Record was changed by another user",
+		"conflict": true,
+		"current":  currentValues,
+		"headers":  headers,
+		"version":  formatRecordVersion(current[mConfig.VersionField]),
+	})
+}
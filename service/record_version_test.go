@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestRecordVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"notes.json":   {Data: []byte(`{"fields": ["id", "name"], "editableFields": ["name"], "versionField": "version"}`)},
+		"stamped.json": {Data: []byte(`{"fields": ["id", "name"], "editableFields": ["name"], "versionField": "updated_at"}`)},
+		"texted.json":  {Data: []byte(`{"fields": ["id", "name"], "editableFields": ["name"], "versionField": "updated_at"}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, version INTEGER);
+		INSERT INTO notes (name, version) VALUES ('first', 3), ('second', NULL);
+		CREATE TABLE stamped (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, updated_at DATETIME);
+		CREATE TABLE texted (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, updated_at TEXT);
+		INSERT INTO texted (name, updated_at) VALUES ('fourth', '2026-01-02 03:04:05')`).Error; err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+	if err := s.DB.Exec(`INSERT INTO stamped (name, updated_at) VALUES ('third', ?)`, time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	update := func(payload string) (int, map[string]interface{}) {
+		ctx := model.NewRequest(httptest.NewRequest("POST", "/wedyta/update", strings.NewReader(payload)), nil)
+		s.Update(ctx)
+		var result map[string]interface{}
+		if err := json.Unmarshal(ctx.Response.Body, &result); err != nil {
+			t.Fatalf("can't parse the response %s: %v", ctx.Response.Body, err)
+		}
+		return ctx.Response.Status, result
+	}
+
+	if status, result := update(`{"modelName": "notes", "id": 1, "name": "changed", "_version": "2"}`); status != http.StatusConflict || result["current"].(map[string]interface{})["name"] != "first" || result["version"] != "3" {
+		t.Errorf("expected the conflict of the stale version, got %d %v", status, result)
+	}
+	if status, result := update(`{"modelName": "notes", "id": 1, "name": "changed", "_version": "3"}`); status != http.StatusOK || result["version"] != "4" {
+		t.Errorf("expected the update of the current version, got %d %v", status, result)
+	}
+	if status, _ := update(`{"modelName": "notes", "id": 2, "name": "changed"}`); status != http.StatusBadRequest {
+		t.Errorf("expected the missing version to be rejected, got %d", status)
+	}
+
+	// the record without the version matches the empty version only
+	if status, result := update(`{"modelName": "notes", "id": 2, "name": "changed", "_version": "1"}`); status != http.StatusConflict {
+		t.Errorf("expected the conflict of the NULL version, got %d %v", status, result)
+	}
+	if status, result := update(`{"modelName": "notes", "id": 2, "name": "changed", "_version": ""}`); status != http.StatusOK || result["version"] != "1" {
+		t.Errorf("expected the update of the NULL version, got %d %v", status, result)
+	}
+
+	mConfig, err := s.prepareModelConfig(ctx, "stamped", nil)
+	if err != nil {
+		t.Fatalf("prepareModelConfig failed: %v", err)
+	}
+	version, err := s.takeRecordVersion(s.DB, mConfig, 1)
+	if err != nil {
+		t.Fatalf("takeRecordVersion failed: %v", err)
+	}
+	status, result := update(`{"modelName": "stamped", "id": 1, "name": "changed", "_version": "` + version + `"}`)
+	if status != http.StatusOK || result["version"] == version {
+		t.Errorf("expected the update of the timestamp version %s, got %d %v", version, status, result)
+	}
+	if status, _ := update(`{"modelName": "stamped", "id": 1, "name": "again", "_version": "` + version + `"}`); status != http.StatusConflict {
+		t.Errorf("expected the conflict of the stale timestamp version, got %d", status)
+	}
+	if status, result := update(`{"modelName": "stamped", "id": 1, "name": "again", "_version": "` + result["version"].(string) + `"}`); status != http.StatusOK {
+		t.Errorf("expected the update of the new timestamp version, got %d %v", status, result)
+	}
+
+	// the time returned by the driver as text is compared as it was rendered
+	if status, result := update(`{"modelName": "texted", "id": 1, "name": "changed", "_version": "2026-01-02 03:04:04"}`); status != http.StatusConflict {
+		t.Errorf("expected the conflict of the stale text version, got %d %v", status, result)
+	}
+	status, result = update(`{"modelName": "texted", "id": 1, "name": "changed", "_version": "2026-01-02 03:04:05"}`)
+	if status != http.StatusOK || result["version"] == "2026-01-02 03:04:05" {
+		t.Errorf("expected the update of the text version, got %d %v", status, result)
+	}
+	if status, result := update(`{"modelName": "texted", "id": 1, "name": "again", "_version": "` + result["version"].(string) + `"}`); status != http.StatusOK {
+		t.Errorf("expected the update of the new text version, got %d %v", status, result)
+	}
+	if status, _ := update(`{"modelName": "texted", "id": 1, "name": "again", "_version": "yesterday"}`); status != http.StatusBadRequest {
+		t.Errorf("expected the invalid version to be rejected, got %d", status)
+	}
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const registeredDateTimeFormat = "2006-01-02 15:04:05"
+
+type registeredModel struct {
+	value   interface{}
+	options model.ModelOptions
+}
+
+var (
+	registryMu       sync.RWMutex
+	registeredModels = make(map[string]registeredModel)
+)
+
+// RegisterModel makes the GORM struct available as a model, its config is derived from the GORM schema and `wedyta` struct tags,
+// see model.ModelOptions. A config file of the same model name takes precedence over the registered struct.
+// It panics if the value isn't a struct or a pointer to it.
+func RegisterModel(value interface{}, opts model.ModelOptions) {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("WeDyTa: RegisterModel expects a struct, got %T", value))
+	}
+
+	modelName := opts.ModelName
+	if modelName == "" {
+		modelName = t.Name()
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registeredModels[modelName] = registeredModel{value: value, options: opts}
+}
+
+func lookupRegisteredModel(modelName string) (registeredModel, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	reg, found := registeredModels[modelName]
+	return reg, found
+}
+
+// buildRegisteredModelConfig derives the raw config of the registered struct, the same that json.Unmarshal gives for a config file
+func (s *Service) buildRegisteredModelConfig(reg registeredModel) (*model.ConfigOfModel, error) {
+	stmt := &gorm.Statement{DB: s.DB}
+	if err := stmt.Parse(reg.value); err != nil {
+		return nil, fmt.Errorf("can't parse GORM schema of %T: %v", reg.value, err)
+	}
+	sch := stmt.Schema
+
+	mConfig := reg.options.Config
+	mConfig.Headers = cloneOrMake(mConfig.Headers)
+	mConfig.Titles = cloneOrMake(mConfig.Titles)
+	mConfig.DisplayMode = cloneOrMake(mConfig.DisplayMode)
+	mConfig.DateTimeFields = cloneOrMake(mConfig.DateTimeFields)
+	mConfig.RelatedData = cloneOrMake(mConfig.RelatedData)
+	mConfig.FieldEditor = cloneOrMake(mConfig.FieldEditor)
+	mConfig.Password = cloneOrMake(mConfig.Password)
+	mConfig.Links = cloneOrMake(mConfig.Links)
+
+	if mConfig.DbTable == "" {
+		mConfig.DbTable = sch.Table
+	}
+	if mConfig.DbTablePrimaryKey == "" && sch.PrioritizedPrimaryField != nil {
+		mConfig.DbTablePrimaryKey = sch.PrioritizedPrimaryField.DBName
+	}
+
+	var fields, editable, addable, required []string
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		tag := schema.ParseTagSetting(field.Tag.Get("wedyta"), ";")
+		if _, skip := tag["-"]; skip {
+			continue
+		}
+
+		if field.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			if !mConfig.SoftDelete.Enabled() {
+				mConfig.SoftDelete.Field = field.DBName
+			}
+			continue
+		}
+
+		name := field.DBName
+		fields = append(fields, name)
+
+		setIfAbsent(mConfig.Headers, name, tag["HEADER"])
+		if mConfig.Headers[name] == "" {
+			mConfig.Headers[name] = utils.HumanizeName(name)
+		}
+		setIfAbsent(mConfig.Titles, name, tag["TITLE"])
+		setIfAbsent(mConfig.DisplayMode, name, tag["DISPLAYMODE"])
+
+		if field.DataType == schema.Time {
+			format := tag["FORMAT"]
+			if format == "" {
+				format = registeredDateTimeFormat
+			}
+			setIfAbsent(mConfig.DateTimeFields, name, format)
+		}
+
+		if editor := tag["EDITOR"]; editor != "" {
+			if _, exists := mConfig.FieldEditor[name]; !exists {
+				mConfig.FieldEditor[name] = map[string]interface{}{"type": editor}
+			}
+		}
+
+		if _, exists := tag["PASSWORD"]; exists {
+			if _, exists := mConfig.Password[name]; !exists {
+				mConfig.Password[name] = map[string]string{}
+			}
+		}
+
+		if _, exists := tag["EDITABLE"]; exists {
+			editable = append(editable, name)
+		}
+		if _, exists := tag["ADDABLE"]; exists {
+			addable = append(addable, name)
+		}
+		if _, exists := tag["REQUIRED"]; exists {
+			required = append(required, name)
+		}
+	}
+
+	if len(mConfig.Fields) == 0 {
+		mConfig.Fields = fields
+	}
+	if len(mConfig.EditableFields) == 0 {
+		mConfig.EditableFields = editable
+	}
+	if len(mConfig.AddableFields) == 0 {
+		mConfig.AddableFields = addable
+	}
+	if len(mConfig.RequiredFields) == 0 {
+		mConfig.RequiredFields = required
+	}
+
+	// belongs-to relations
+	for _, rel := range sch.Relationships.BelongsTo {
+		if len(rel.References) != 1 || rel.FieldSchema == nil {
+			continue
+		}
+		ref := rel.References[0]
+		if ref.ForeignKey == nil || ref.PrimaryKey == nil {
+			continue
+		}
+
+		fk := ref.ForeignKey.DBName
+		if _, exists := mConfig.RelatedData[fk]; exists {
+			continue
+		}
+
+		tag := schema.ParseTagSetting(ref.ForeignKey.Tag.Get("wedyta"), ";")
+		valueField := tag["RELATED"]
+		if valueField == "" {
+			valueField = guessValueField(rel.FieldSchema)
+		}
+		if valueField == "" {
+			valueField = ref.PrimaryKey.DBName
+		}
+
+		mConfig.RelatedData[fk] = model.RelatedDataEntry{
+			Table:      rel.FieldSchema.Table,
+			KeyField:   ref.PrimaryKey.DBName,
+			ValueField: valueField,
+		}
+	}
+
+	if unknown := applyFieldPresets(&mConfig); len(unknown) > 0 {
+		return nil, unknownFieldPresetsError(unknown)
+	}
+
+	return &mConfig, nil
+}
+
+// guessValueField returns the label column of the related schema or its first string column
+func guessValueField(sch *schema.Schema) string {
+	if valueField := utils.GuessLabelField(sch.DBNames); valueField != "" {
+		return valueField
+	}
+
+	for _, field := range sch.Fields {
+		if field.DBName != "" && !field.PrimaryKey && field.DataType == schema.String {
+			return field.DBName
+		}
+	}
+
+	return ""
+}
+
+func cloneOrMake[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return maps.Clone(m)
+}
+
+func setIfAbsent(m map[string]string, key, value string) {
+	if value == "" || strings.TrimSpace(m[key]) != "" {
+		return
+	}
+	m[key] = value
+}
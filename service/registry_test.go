@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type registryTestUser struct {
+	ID       uint
+	Username string
+}
+
+type registryTestArticle struct {
+	ID          uint
+	Title       string `wedyta:"header:Headline;editable;addable;required"`
+	Body        string `wedyta:"editor:summernote;displayMode:record,update"`
+	Secret      string `wedyta:"-"`
+	AuthorID    uint
+	Author      registryTestUser
+	PublishedAt time.Time `wedyta:"format:2006-01-02"`
+	DeletedAt   gorm.DeletedAt
+}
+
+func TestBuildRegisteredModelConfig(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	s := &Service{DB: db}
+
+	mConfig, err := s.buildRegisteredModelConfig(registeredModel{
+		value:   &registryTestArticle{},
+		options: model.ModelOptions{Config: model.ConfigOfModel{PageTitle: "Articles", Headers: map[string]string{"body": "Text"}}},
+	})
+	if err != nil {
+		t.Fatalf("buildRegisteredModelConfig failed: %v", err)
+	}
+
+	if mConfig.DbTable != "registry_test_articles" || mConfig.DbTablePrimaryKey != "id" || mConfig.PageTitle != "Articles" {
+		t.Errorf("unexpected table settings: %q %q %q", mConfig.DbTable, mConfig.DbTablePrimaryKey, mConfig.PageTitle)
+	}
+	if expected := []string{"id", "title", "body", "author_id", "published_at"}; !reflect.DeepEqual(mConfig.Fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, mConfig.Fields)
+	}
+	if mConfig.Headers["title"] != "Headline" || mConfig.Headers["body"] != "Text" || mConfig.Headers["author_id"] != "Author id" {
+		t.Errorf("unexpected headers: %v", mConfig.Headers)
+	}
+	if !reflect.DeepEqual(mConfig.EditableFields, []string{"title"}) || !reflect.DeepEqual(mConfig.RequiredFields, []string{"title"}) {
+		t.Errorf("unexpected editable %v or required %v", mConfig.EditableFields, mConfig.RequiredFields)
+	}
+	if mConfig.FieldEditor["body"]["type"] != "summernote" || mConfig.DisplayMode["body"] != "record,update" {
+		t.Errorf("unexpected body settings: %v %v", mConfig.FieldEditor, mConfig.DisplayMode)
+	}
+	if mConfig.DateTimeFields["published_at"] != "2006-01-02" {
+		t.Errorf("unexpected dateTimeFields: %v", mConfig.DateTimeFields)
+	}
+	if mConfig.SoftDelete.Field != "deleted_at" {
+		t.Errorf("expected softDelete by deleted_at, got %q", mConfig.SoftDelete.Field)
+	}
+
+	expectedRelated := model.RelatedDataEntry{Table: "registry_test_users", KeyField: "id", ValueField: "username"}
+	if mConfig.RelatedData["author_id"] != expectedRelated {
+		t.Errorf("expected relatedData %+v, got %+v", expectedRelated, mConfig.RelatedData["author_id"])
+	}
+}
+
+func TestRegisterModelPanics(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered == nil || !strings.Contains(fmt.Sprint(recovered), "expects a struct, got string") {
+			t.Errorf("expected the panic of the non-struct value, got %v", recovered)
+		}
+	}()
+	RegisterModel("items", model.ModelOptions{})
+}
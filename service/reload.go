@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"slices"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"golang.org/x/text/language"
+)
+
+// Reload re-reads the configs of the loaded models and the templates and drops the cached table schemas, e.g. by a deploy script after a migration.
+// The new configs replace the old ones at once, the models failing to load are dropped from the cache and their errors are returned.
+func (s *Service) Reload() error {
+	sqlutils.InvalidateTableSchema()
+
+	if err := s.validateConfigs(); err != nil {
+		return fmt.Errorf("WeDyTa: the configs are not reloaded due to issues:\n%w", err)
+	}
+
+	templates, err := s.parseTemplates()
+	if err != nil {
+		return err
+	}
+	s.cacheMu.Lock()
+	s.templates = templates
+	s.templatesByLocale = make(map[language.Tag]*template.Template)
+	s.cacheMu.Unlock()
+
+	return s.rebuildModelCache(func(string, model.CachedModelConfig) bool {
+		return true
+	})
+}
+
+// validateConfigs checks the configs by ConfigValidation: "unavailable" marks the models with issues unavailable,
+// "strict" returns the issues as the error
+func (s *Service) validateConfigs() error {
+	switch s.Config.ConfigValidation {
+	case model.ConfigValidationUnavailable:
+		unavailableModels := s.Validate().ByModel()
+		for modelName, issues := range unavailableModels {
+			log.Printf("WeDyTa: model %s is unavailable due to config issues:\n%v", modelName, issues.Error())
+		}
+		s.cacheMu.Lock()
+		s.unavailableModels = unavailableModels
+		s.cacheMu.Unlock()
+	case model.ConfigValidationStrict:
+		if issues := s.Validate(); len(issues) > 0 {
+			return issues
+		}
+	}
+	return nil
+}
+
+// reloadConfigFiles re-reads the cached models using any of the files and the models named after them,
+// the configs are validated the same way as by Reload
+func (s *Service) reloadConfigFiles(names []string) error {
+	if err := s.validateConfigs(); err != nil {
+		return fmt.Errorf("the configs are not reloaded due to issues:\n%w", err)
+	}
+
+	return s.rebuildModelCache(func(modelName string, cached model.CachedModelConfig) bool {
+		for _, name := range names {
+			if _, uses := cached.FileVersions[name]; uses || configModelName(name) == modelName {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// rebuildModelCache re-reads the stale cached models with their children and swaps the cache at once,
+// requests being served keep the configs they have got
+func (s *Service) rebuildModelCache(isStale func(modelName string, cached model.CachedModelConfig) bool) error {
+	s.cacheMu.RLock()
+	configs := make(map[string]model.CachedModelConfig, len(s.modelCache))
+	var stale []string
+	for modelName, cached := range s.modelCache {
+		if isStale(modelName, cached) {
+			stale = append(stale, modelName)
+		} else {
+			configs[modelName] = cached
+		}
+	}
+	s.cacheMu.RUnlock()
+
+	// children reference the config of the parent, so they are stale with it
+	for dropped := true; dropped; {
+		dropped = false
+		for modelName, cached := range configs {
+			if _, kept := configs[cached.Config.Parent.ModelName]; cached.Config.HasParent && !kept {
+				delete(configs, modelName)
+				stale = append(stale, modelName)
+				dropped = true
+			}
+		}
+	}
+
+	var load func(modelName string, chain []string) (*model.ConfigOfModel, error)
+	load = func(modelName string, chain []string) (*model.ConfigOfModel, error) {
+		if cached, found := configs[modelName]; found {
+			return cached.Config, nil
+		}
+		cached, err := s.readModelConfig(modelName, chain, load)
+		if err != nil {
+			return nil, err
+		}
+		configs[modelName] = cached
+		return cached.Config, nil
+	}
+
+	var errs []error
+	slices.Sort(stale)
+	for _, modelName := range stale {
+		if _, err := load(modelName, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.cacheMu.Lock()
+	s.modelCache = configs
+	s.cacheMu.Unlock()
+
+	return errors.Join(errs...)
+}
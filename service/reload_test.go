@@ -0,0 +1,139 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestReloadConfigFiles_Dependents(t *testing.T) {
+	fsys := fstest.MapFS{
+		"owners.json": {Data: []byte(`{"dbTable": "items", "pageTitle": "Owners", "fields": ["id"]}`)},
+		"items.json":  {Data: []byte(`{"dbTable": "items", "fields": ["id", "owner_id"], "parent": {"modelName": "owners", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+		"other.json":  {Data: []byte(`{"dbTable": "items", "fields": ["id"]}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+
+	items, err := s.cachedModelConfig("items")
+	if err != nil {
+		t.Fatalf("cachedModelConfig failed: %v", err)
+	}
+	other, _ := s.cachedModelConfig("other")
+
+	fsys["owners.json"] = &fstest.MapFile{Data: []byte(`{"dbTable": "items", "pageTitle": "Renamed", "fields": ["id"]}`)}
+	if err := s.reloadConfigFiles([]string{"owners.json"}); err != nil {
+		t.Fatalf("reloadConfigFiles failed: %v", err)
+	}
+
+	reloaded, _ := s.cachedModelConfig("items")
+	if reloaded == items || reloaded.ParentConfig.PageTitle != "Renamed" {
+		t.Errorf("expected the child to reference the reloaded parent, got %q", reloaded.ParentConfig.PageTitle)
+	}
+	if kept, _ := s.cachedModelConfig("other"); kept != other {
+		t.Errorf("expected the unrelated model to stay cached")
+	}
+}
+
+func TestReload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json":  {Data: []byte(`{"pageTitle": "First", "fields": ["id"]}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "items"}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+
+	first, _ := s.cachedModelConfig("items")
+	if _, err := s.prepareModelConfig(ctx, "broken", nil); err != nil {
+		t.Fatalf("prepareModelConfig failed: %v", err)
+	}
+
+	fsys["broken.json"] = &fstest.MapFile{Data: []byte(`{"dbTable": `)}
+	if err := s.Reload(); err == nil {
+		t.Errorf("expected the error of the broken config")
+	}
+
+	reloaded, err := s.cachedModelConfig("items")
+	if err != nil || reloaded == first || reloaded.PageTitle != "First" {
+		t.Errorf("expected a reloaded config, err: %v", err)
+	}
+	if _, found := s.modelCache["broken"]; found {
+		t.Errorf("expected the broken model to be dropped from the cache")
+	}
+}
+
+func TestWatchConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "items.json")
+	if err := os.WriteFile(configFile, []byte(`{"pageTitle": "First", "fields": ["id"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT);`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	if _, err := NewServiceE(db, &model.WedytaConfig{ConfigDir: dir, ConfigFS: fstest.MapFS{}, WatchConfigDir: true, AssetsSource: model.AssetsSourceCDN}); err == nil {
+		t.Errorf("expected the watcher to be refused with a custom ConfigFS")
+	}
+
+	s := NewService(db, &model.WedytaConfig{ConfigDir: dir, ConfigFS: os.DirFS(dir), WatchConfigDir: true, AssetsSource: model.AssetsSourceCDN})
+	defer s.Close()
+	if s.watcher == nil {
+		t.Fatalf("watcher is not started")
+	}
+
+	if mConfig, err := s.cachedModelConfig("items"); err != nil || mConfig.PageTitle != "First" {
+		t.Fatalf("unexpected config, err: %v", err)
+	}
+
+	if err := os.WriteFile(configFile, []byte(`{"pageTitle": "Second", "fields": ["id"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if mConfig, _ := s.cachedModelConfig("items"); mConfig != nil && mConfig.PageTitle == "Second" {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Errorf("the changed config is not reloaded")
+}
+
+func TestReload_Strict(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"pageTitle": "First", "fields": ["id"]}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	s.Config.ConfigValidation = model.ConfigValidationStrict
+	first, _ := s.cachedModelConfig("items")
+
+	fsys["items.json"] = &fstest.MapFile{Data: []byte(`{"pageTitle": "Second", "fields": ["id", "colour"]}`)}
+	if err := s.Reload(); err == nil || !strings.Contains(err.Error(), `unknown column "colour"`) {
+		t.Errorf("expected the issues of the config, got %v", err)
+	}
+	if err := s.reloadConfigFiles([]string{"items.json"}); err == nil {
+		t.Errorf("expected the watcher reload to fail on the issues")
+	}
+	if kept, _ := s.cachedModelConfig("items"); kept != first {
+		t.Errorf("expected the loaded config to be kept")
+	}
+
+	fsys["items.json"] = &fstest.MapFile{Data: []byte(`{"pageTitle": "Second", "fields": ["id", "name"]}`)}
+	if err := s.Reload(); err != nil {
+		t.Errorf("Reload failed: %v", err)
+	}
+	if reloaded, _ := s.cachedModelConfig("items"); reloaded.PageTitle != "Second" {
+		t.Errorf("expected the reloaded config, got %q", reloaded.PageTitle)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"html/template"
+	"strconv"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+// renderChildTables renders the tables of the "children" models filtered by the record as tabs, the children without read access are skipped.
+// The tables omit the editing scripts, scripts reports whether the record page has to load them.
+func (s *Service) renderChildTables(ctx *model.Request, mConfig *model.ConfigOfModel, recID int64) (html template.HTML, scripts bool, err error) {
+	var tabs []tabData
+	for _, child := range mConfig.Children {
+		opts := model.RenderOptions{ParentValue: strconv.FormatInt(recID, 10), HideBreadcrumbs: true, HideScripts: true}
+		childCtx, childConfig, opts, err := s.prepareFragment(ctx, child, "read", opts)
+		if errors.Is(err, ErrAccessDenied) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+
+		table, err := s.renderModelTable(childCtx, s.DB, childConfig, opts)
+		if err != nil {
+			return "", false, err
+		}
+		tabs = append(tabs, tabData{Title: childConfig.PageTitle, Content: template.HTML(table)})
+		scripts = scripts || hasEditingScripts(childConfig)
+	}
+
+	if len(tabs) == 0 {
+		return "", false, nil
+	}
+
+	html, err = s.wrapBsTabs(mConfig, "children", tabs)
+	return html, scripts, err
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestRenderChildTables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"owners.json": {Data: []byte(`{"pageTitle": "Owners", "fields": ["id", "name"], "children": ["items", "secretItems"]}`)},
+		"items.json": {Data: []byte(`{"pageTitle": "Owned items", "fields": ["id", "name", "owner_id"], "addableFields": ["name"], "editableFields": ["name"],
+			"parent": {"modelName": "owners", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+		"secretItems.json": {Data: []byte(`{"dbTable": "items", "pageTitle": "Secret items", "fields": ["id"],
+			"parent": {"modelName": "owners", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "owners", "fields": ["id"], "children": ["items", "missing"]}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE owners (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT); INSERT INTO owners (name) VALUES ('ann'), ('bob');
+		INSERT INTO items (name, owner_id) VALUES ('apple', 1), ('pear', 2), ('plum', 2)`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return modelName != "secretItems"
+	}
+
+	record, err := s.RenderRecordFragment(nil, "owners", 2, model.RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderRecordFragment failed: %v", err)
+	}
+	html := string(record)
+	for _, expected := range []string{"bob", `id="childrenTabs"`, ">Owned items</button>", "pear", "plum", `name="owner" value="2"`, "wedyta_update.js"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %q in\n%s", expected, html)
+		}
+	}
+	for _, unexpected := range []string{"apple", "Secret items"} {
+		if strings.Contains(html, unexpected) {
+			t.Errorf("unexpected %q in\n%s", unexpected, html)
+		}
+	}
+	if count := strings.Count(html, "wedyta_update.js"); count != 1 {
+		t.Errorf("expected the editing scripts once, got %d", count)
+	}
+
+	s.Config.PaginationRecordsPerPage = 1
+	record, err = s.RenderRecordFragment(nil, "owners", 2, model.RenderOptions{})
+	s.Config.PaginationRecordsPerPage = 100
+	if err != nil {
+		t.Fatalf("RenderRecordFragment failed: %v", err)
+	}
+	if html := string(record); !strings.Contains(html, `href="/wedyta/items?owner=2&amp;page=2"`) {
+		t.Errorf("expected the pagination of the child table to lead to its route in\n%s", html)
+	}
+
+	issues := s.ValidateModel("broken")
+	if len(issues) != 2 || issues[0].Path != "children.0" || issues[1].Path != "children.1" {
+		t.Errorf("expected the issues of the children, got:\n%v", issues.Error())
+	}
+}
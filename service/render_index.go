@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+)
+
+// RenderIndex lists the models readable by the user grouped by "group" with their record counts,
+// child models requiring a query variable of the parent record are not listed
+func (s *Service) RenderIndex(ctx *model.Request) {
+	modelNames, err := s.ModelNames()
+	if err != nil {
+		s.SomethingWentWrong(ctx, "RenderIndex: can't list models: "+err.Error())
+		return
+	}
+
+	groups := make(map[string][]indexEntryData)
+	for _, modelName := range modelNames {
+		if s.Config.AccessCheckFunc(ctx, modelName, "", "read") != true {
+			continue
+		}
+
+		mConfig, err := s.prepareModelConfig(ctx, modelName, nil)
+		if err != nil {
+			log.Printf("WeDyTa: index skips model %s: %v", modelName, err)
+			continue
+		}
+		if mConfig.Parent.QueryVariableName != "" {
+			continue
+		}
+
+		count, err := sqlutils.GetTotalRecords(s.DB.Scopes(notSoftDeleted(mConfig)), mConfig)
+		if err != nil {
+			log.Printf("WeDyTa: index can't count records of model %s: %v", modelName, err)
+			count = -1
+		}
+
+		groups[mConfig.Group] = append(groups[mConfig.Group], indexEntryData{ModelName: modelName, Title: mConfig.PageTitle, Count: count})
+	}
+
+	page := &model.ConfigOfModel{Locale: s.resolveLocale(ctx)}
+	page.PageTitle = s.translate(page, s.Config.IndexPageTitle)
+
+	// models without a group go first
+	data := indexData{Title: page.PageTitle}
+	for _, group := range mapKeys(groups) {
+		entries := groups[group]
+		slices.SortStableFunc(entries, func(a, b indexEntryData) int {
+			return strings.Compare(a.Title, b.Title)
+		})
+		data.Groups = append(data.Groups, indexGroupData{Name: group, Entries: entries})
+	}
+
+	htmlContent, err := s.renderTemplate(page, "index", data)
+	if err != nil {
+		s.SomethingWentWrong(ctx, "RenderIndex: "+err.Error())
+		return
+	}
+
+	s.RenderPage(ctx, page, string(htmlContent))
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestRenderIndex(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json":    {Data: []byte(`{"pageTitle": "All items", "fields": ["id"]}`)},
+		"archive.json":  {Data: []byte(`{"dbTable": "items", "pageTitle": "Archive", "group": "Storage", "sqlWhere": "id > 1", "fields": ["id"]}`)},
+		"secret.json":   {Data: []byte(`{"dbTable": "items", "pageTitle": "Secret", "fields": ["id"]}`)},
+		"children.json": {Data: []byte(`{"dbTable": "items", "pageTitle": "Children", "fields": ["id"], "parent": {"modelName": "items", "localConnectionField": "owner_id", "queryVariableName": "owner"}}`)},
+		"_common.json":  {Data: []byte(`{"pageTitle": "Fragment"}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`INSERT INTO items (name) VALUES ('a'), ('b'), ('c')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	s.Config.AccessCheckFunc = func(ctx *model.Request, modelName, fieldName, action string) bool {
+		return modelName != "secret"
+	}
+
+	ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/", nil), nil)
+	s.RenderIndex(ctx)
+
+	body := string(ctx.Response.Body)
+	for _, expected := range []string{
+		`href="/wedyta/items">All items <span class="badge bg-secondary rounded-pill">3</span>`,
+		`<h5 class="mt-3">Storage</h5>`,
+		`href="/wedyta/archive">Archive <span class="badge bg-secondary rounded-pill">2</span>`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in the index", expected)
+		}
+	}
+	for _, unexpected := range []string{"Secret", "Children", "Fragment"} {
+		if strings.Contains(body, unexpected) {
+			t.Errorf("unexpected %q in the index", unexpected)
+		}
+	}
+	if strings.Index(body, "All items") > strings.Index(body, "Storage") {
+		t.Errorf("expected the models without a group before the groups")
+	}
+}
@@ -1,42 +1,39 @@
 package service
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/pa-pe/wedyta/embed"
-	"github.com/pa-pe/wedyta/model"
 	"html/template"
 	"net/http"
-	"strings"
+
+	"github.com/pa-pe/wedyta/model"
 )
 
-func (s *Service) RenderPage(ctx *gin.Context, mConfig *model.ConfigOfModel, htmlContent string) {
+func (s *Service) RenderPage(ctx *model.Request, mConfig *model.ConfigOfModel, htmlContent string) {
 	if s.Config.Template != "" {
-		ginH := gin.H{
+		h := model.H{
 			"HeaderTags": template.HTML(mConfig.HeaderTags),
 			"Title":      mConfig.PageTitle,
 			"Content":    template.HTML(htmlContent),
+			"Lang":       s.i18n.pageLang(mConfig.Locale),
 		}
-		//ginH["Title"] = mConfig.PageTitle
+		//h["Title"] = mConfig.PageTitle
 
 		if s.Config.PrepareTemplateVariables != nil {
-			s.Config.PrepareTemplateVariables(ctx, mConfig.ModelName, ginH)
+			s.Config.PrepareTemplateVariables(ctx, mConfig.ModelName, h)
 		}
 
-		ctx.HTML(http.StatusOK, s.Config.Template, ginH)
+		ctx.HTML(http.StatusOK, s.Config.Template, h)
 	} else {
-		defaultTemplate := "templates/default.tmpl"
-		content, err := embed.EmbeddedFiles.ReadFile(defaultTemplate)
+		page, err := s.renderTemplate(mConfig, layoutTemplate, model.H{
+			"HeaderTags": template.HTML(mConfig.HeaderTags),
+			"Title":      mConfig.PageTitle,
+			"Content":    template.HTML(htmlContent),
+			"Lang":       s.i18n.pageLang(mConfig.Locale),
+		})
 		if err != nil {
-			s.SomethingWentWrong(ctx, "Failed to load default template: "+defaultTemplate)
+			s.SomethingWentWrong(ctx, "Failed to render template "+layoutTemplate+": "+err.Error())
 			return
 		}
 
-		templateContent := string(content)
-
-		templateContent = strings.Replace(templateContent, "{{ .HeaderTags }}", mConfig.HeaderTags, -1)
-		templateContent = strings.Replace(templateContent, "{{ .Title }}", mConfig.PageTitle, -1)
-		templateContent = strings.Replace(templateContent, "{{ .Content }}", htmlContent, -1)
-
-		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(templateContent))
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
 	}
 }
@@ -2,30 +2,24 @@ package service
 
 import (
 	"fmt"
-	"html"
-	"strings"
+	"html/template"
 
 	"github.com/pa-pe/wedyta/model"
 )
 
-func (s *Service) renderFormInputTag(fldCfg *model.FieldParams, mConfig *model.ConfigOfModel, record map[string]interface{}, value interface{}) (string, string) {
+func (s *Service) renderFormInputTag(fldCfg *model.FieldParams, mConfig *model.ConfigOfModel, record map[string]interface{}, value interface{}) (template.HTML, template.HTML, error) {
 	field := fldCfg.Field
-	var htmlTag strings.Builder
 
-	titleStr := ""
-	if fldCfg.Title != "" {
-		titleStr = fmt.Sprintf(" title='%s'", fldCfg.Title)
+	labelTag, err := s.renderTemplate(mConfig, "form_label", formLabelData{
+		Field:    field,
+		Title:    fldCfg.Title,
+		Header:   template.HTML(fldCfg.Header),
+		Required: fldCfg.IsRequired,
+	})
+	if err != nil {
+		return "", "", err
 	}
 
-	requiredAttr := ""
-	requiredLabel := ""
-	if fldCfg.IsRequired {
-		requiredAttr = " required"
-		requiredLabel = ` <span class="required-label">(required)</span>`
-	}
-
-	labelTag := fmt.Sprintf("<label%s for=\"%s\" class=\"form-label\" id=\"header_of_%s\">%s</label>%s", titleStr, field, field, fldCfg.Header, requiredLabel)
-
 	var value_ interface{}
 	if record == nil {
 		value_ = value
@@ -33,47 +27,47 @@ func (s *Service) renderFormInputTag(fldCfg *model.FieldParams, mConfig *model.C
 		value_ = takeFieldValueFromRecord(field, record)
 	}
 
+	input := formInputData{
+		Field:    field,
+		Editor:   fldCfg.FieldEditor,
+		Value:    fmt.Sprintf("%v", value),
+		Required: fldCfg.IsRequired,
+		Editable: fldCfg.IsEditable,
+	}
+
 	switch fldCfg.FieldEditor {
-	case "textarea":
-		htmlTag.WriteString(fmt.Sprintf("<textarea class=\"form-control\" id=\"%s\" name=\"%s\"%s>%v</textarea>", field, field, requiredAttr, value))
-	case "input":
-		htmlTag.WriteString(fmt.Sprintf("<input class=\"form-control\" type=\"text\" id=\"%s\" name=\"%s\" value=\"%v\"%s>", field, field, value, requiredAttr))
 	case "select":
-		htmlSelect, err := s.RenderRelatedDataSelect(fldCfg, value_)
+		htmlSelect, err := s.renderRelatedDataSelect(mConfig, fldCfg, value_)
 		if err != nil {
-			htmlTag.WriteString("oops")
+			input.Select = "oops"
 		} else {
-			htmlTag.WriteString(htmlSelect)
+			input.Select = htmlSelect
 		}
-	case "summernote":
-		htmlTag.WriteString(fmt.Sprintf("<textarea class=\"form-control\" id=\"%s\" name=\"%s\"%s>%v</textarea>", field, field, requiredAttr, value))
 	case "bs5switch":
-		var pkValue string
 		if record != nil {
 			pkValueI, exists := record[mConfig.DbTablePrimaryKey]
 			if exists {
-				pkValue = fmt.Sprintf("%v", pkValueI)
+				input.PK = fmt.Sprintf("%v", pkValueI)
 			}
 		}
+		input.Checked = fmt.Sprintf("%v", value_) == "1"
+	}
 
-		checked := ""
-		if fmt.Sprintf("%v", value_) == "1" {
-			checked = " checked"
-		}
-
-		disabled := " disabled"
-		if fldCfg.IsEditable {
-			disabled = ""
-		}
-		htmlTag.WriteString(fmt.Sprintf("<div class=\"form-check form-switch\"><input class=\"form-check-input\" type=\"checkbox\" role=\"switch\" name=\"%s\" rec_id=\"%s\" id=\"%s_%s\"%s%s></div>", field, pkValue, field, pkValue, checked, disabled))
-	default:
-		htmlTag.WriteString("oops, something went wrong")
+	fieldTag, err := s.renderTemplate(mConfig, "form_input", input)
+	if err != nil {
+		return "", "", err
 	}
 
-	return labelTag, htmlTag.String()
+	return labelTag, fieldTag, nil
 }
 
+// RenderRelatedDataSelect renders the select of the relatedData field by the default "select" template
 func (s *Service) RenderRelatedDataSelect(fldCfg *model.FieldParams, selected interface{}) (string, error) {
+	htmlSelect, err := s.renderRelatedDataSelect(nil, fldCfg, selected)
+	return string(htmlSelect), err
+}
+
+func (s *Service) renderRelatedDataSelect(mConfig *model.ConfigOfModel, fldCfg *model.FieldParams, selected interface{}) (template.HTML, error) {
 	var records []map[string]interface{}
 
 	rdCfg := fldCfg.RelatedData
@@ -94,27 +88,15 @@ func (s *Service) RenderRelatedDataSelect(fldCfg *model.FieldParams, selected in
 		}
 	}
 
-	var htmlSelect strings.Builder
-	requiredAttr := ""
-	if fldCfg.IsRequired {
-		requiredAttr = " required"
-	}
-
-	htmlSelect.WriteString(`<select class="form-select" name="` + fldCfg.Field + `"` + requiredAttr + `>` + "\n")
-	htmlSelect.WriteString(fmt.Sprintf(`<option value="%s"%s>%s</option>`+"\n", "0", "", ""))
-
+	data := selectData{Field: fldCfg.Field, Required: fldCfg.IsRequired}
 	for _, record := range records {
 		id := fmt.Sprint(record[rdCfg.KeyField])
-		text := fmt.Sprint(record[rdCfg.ValueField])
-
-		selectedAttr := ""
-		if selected != nil && fmt.Sprint(selected) == id {
-			selectedAttr = ` selected`
-		}
-
-		htmlSelect.WriteString(fmt.Sprintf(`<option value="%s"%s>%s</option>`+"\n", html.EscapeString(id), selectedAttr, html.EscapeString(text)))
+		data.Options = append(data.Options, selectOption{
+			Value:    id,
+			Text:     fmt.Sprint(record[rdCfg.ValueField]),
+			Selected: selected != nil && fmt.Sprint(selected) == id,
+		})
 	}
 
-	htmlSelect.WriteString(`</select>` + "\n")
-	return htmlSelect.String(), nil
+	return s.renderTemplate(mConfig, "select", data)
 }
@@ -2,12 +2,13 @@ package service
 
 import (
 	"fmt"
-	"github.com/gin-gonic/gin"
+	"html/template"
+	"log"
+	"strings"
+
 	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils"
 	"github.com/pa-pe/wedyta/utils/sqlutils"
-	"log"
-	"strings"
 )
 
 func takeFieldValueFromRecord(field string, record map[string]interface{}) interface{} {
@@ -22,7 +23,8 @@ func takeFieldValueFromRecord(field string, record map[string]interface{}) inter
 	return value
 }
 
-func (s *Service) renderRecordValue(ctx *gin.Context, mConfig *model.ConfigOfModel, field string, record map[string]interface{}, cache *model.RenderTableCache) (interface{}, string) {
+// renderRecordValue returns the value of the field and the table cell showing it, the value of the cell is HTML
+func (s *Service) renderRecordValue(ctx *model.Request, mConfig *model.ConfigOfModel, field string, record map[string]interface{}, cache *model.RenderTableCache) (interface{}, cellData) {
 	value := takeFieldValueFromRecord(field, record)
 
 	var pkValue string
@@ -32,43 +34,47 @@ func (s *Service) renderRecordValue(ctx *gin.Context, mConfig *model.ConfigOfMod
 	}
 
 	fldCfg := mConfig.FieldConfig[field]
+	fldCfg.IsEditable = fldCfg.IsEditable && !cache.ReadOnly && s.fieldPermitted(ctx, mConfig, field, "update", cache)
 
-	classStr := ""
-	additionalAttr := ""
+	var cell cellData
+	var classes []string
 	if field == "id" || field == "ID" {
-		classStr += " rec_id"
+		classes = append(classes, "rec_id")
 	}
 
 	if fldCfg.Classes != "" {
-		classStr += " " + fldCfg.Classes
+		classes = append(classes, fldCfg.Classes)
 	}
 
 	if fldCfg.IsEditable {
-		classStr += " editable editable-" + fldCfg.FieldEditor
-		additionalAttr += ` fieldName="` + utils.CamelToSnake(field) + `"`
+		classes = append(classes, "editable editable-"+fldCfg.FieldEditor)
+		cell.FieldName = utils.CamelToSnake(field)
 	}
-
-	classAttr := ""
-	if classStr != "" {
-		classStr = classStr[1:]
-		classAttr = fmt.Sprintf(" class='%s'", classStr)
-	}
-	tagAttrs := classAttr + additionalAttr
+	cell.Class = strings.Join(classes, " ")
 
 	columnDataFunc, exists := mConfig.ColumnDataFunc[field]
 	if exists {
-		if columnDataFunc == "stdRecordControls" {
+		if columnDataFunc.Name == "stdRecordControls" {
 			url := "/wedyta/" + mConfig.ModelName + "/" + pkValue + "/update" + mConfig.AdditionalUrlParams
 			value = "<a href=\"" + url + "\"><i class=\"bi-pen record-control-update\"></i></a>"
-			//value = value.(string) + " <i class=\"bi-trash record-control-delete\"></i>"
-		} else if columnDataFunc == "dynamicColumnDataFunc" {
+			if mConfig.Duplicate.Enabled && len(mConfig.AddableFields) > 0 && s.fieldPermitted(ctx, mConfig, "", "create", cache) {
+				title := template.HTMLEscapeString(s.translate(mConfig, "Duplicate"))
+				value = value.(string) + " <a href=\"" + template.HTMLEscapeString(duplicateURL(mConfig, pkValue)) + "\" title=\"" + title + "\"><i class=\"bi-copy record-control-duplicate\"></i></a>"
+			}
+			if mConfig.Deletable && s.fieldPermitted(ctx, mConfig, "", "delete", cache) {
+				value = value.(string) + " <i class=\"bi-trash record-control-delete\" rec_id=\"" + pkValue + "\" style=\"cursor: pointer;\"></i>"
+			}
+			value = value.(string) + s.renderRecordActions(ctx, mConfig, pkValue, cache)
+		} else if columnDataFunc.Name == "dynamicColumnDataFunc" {
 			if s.Config.DynamicColumnDataFunc != nil {
 				value = s.Config.DynamicColumnDataFunc(ctx, s.DB, mConfig.DbTable, field, record)
 			} else {
 				value = "!WedytaConfig.DynamicColumnDataFunc not set"
 			}
+		} else if columnFunc, found := s.lookupColumnFunc(columnDataFunc.Name); found {
+			value = columnFunc(model.Column{Request: ctx, DB: s.DB, Model: mConfig, Field: field, Record: record, Params: columnDataFunc.Params})
 		} else {
-			value = "!Unknown columnDataFunc: " + columnDataFunc
+			value = "!Unknown columnDataFunc: " + columnDataFunc.Name
 		}
 	}
 
@@ -131,9 +137,13 @@ func (s *Service) renderRecordValue(ctx *gin.Context, mConfig *model.ConfigOfMod
 	}
 
 	if fldCfg.FieldEditor == "bs5switch" {
-		_, fieldTag := s.renderFormInputTag(&fldCfg, mConfig, record, value)
+		_, fieldTag, err := s.renderFormInputTag(&fldCfg, mConfig, record, value)
+		if err != nil {
+			log.Printf("WeDyTa: failed to render %s of model %s: %v", field, mConfig.ModelName, err)
+		}
 		value = fieldTag
 	}
 
-	return value, tagAttrs
+	cell.Value = template.HTML(fmt.Sprintf("%v", value))
+	return value, cell
 }
@@ -1,18 +1,18 @@
 package service
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"html/template"
 	"strconv"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/pa-pe/wedyta/model"
 	"github.com/pa-pe/wedyta/utils/sqlutils"
 	"gorm.io/gorm"
 )
 
-func (s *Service) RenderTable(ctx *gin.Context) {
+func (s *Service) RenderTable(ctx *model.Request) {
 	modelName := ctx.Param("modelName")
 
 	action := "read"
@@ -30,20 +30,24 @@ func (s *Service) RenderTable(ctx *gin.Context) {
 	s.RenderPage(ctx, mConfig, htmlTable)
 }
 
-func (s *Service) RenderModelTable(ctx *gin.Context, db *gorm.DB, mConfig *model.ConfigOfModel) (string, error) {
+func (s *Service) RenderModelTable(ctx *model.Request, db *gorm.DB, mConfig *model.ConfigOfModel) (string, error) {
+	pageNum, _ := strconv.Atoi(ctx.Query("page"))
+	return s.renderModelTable(ctx, db, mConfig, model.RenderOptions{Page: pageNum})
+}
+
+func (s *Service) renderModelTable(ctx *model.Request, db *gorm.DB, mConfig *model.ConfigOfModel, opts model.RenderOptions) (string, error) {
 	if mConfig == nil {
-		log.Fatalf("Wedyta: RenderModelTable(): mConfig == nil")
+		return "", errors.New("RenderModelTable(): mConfig == nil")
 	}
 
-	pageNumStr := ctx.Query("page")
-	pageNum, err := strconv.Atoi(pageNumStr)
-	if err != nil || pageNum < 1 {
+	pageNum := opts.Page
+	if pageNum < 1 {
 		pageNum = 1
 	}
 
 	offset := (pageNum - 1) * s.Config.PaginationRecordsPerPage
 
-	totalRecords, err := sqlutils.GetTotalRecords(s.DB, mConfig)
+	totalRecords, err := sqlutils.GetTotalRecords(s.DB.Scopes(notSoftDeleted(mConfig), filtered(opts.Filters)), mConfig)
 	if err != nil {
 		return "", err
 	}
@@ -52,6 +56,7 @@ func (s *Service) RenderModelTable(ctx *gin.Context, db *gorm.DB, mConfig *model
 	if err := db.
 		Table(mConfig.DbTable).
 		Where(mConfig.SqlWhere).
+		Scopes(notSoftDeleted(mConfig), filtered(opts.Filters)).
 		Order(mConfig.OrderBy).
 		Limit(s.Config.PaginationRecordsPerPage).
 		Offset(offset).
@@ -59,75 +64,75 @@ func (s *Service) RenderModelTable(ctx *gin.Context, db *gorm.DB, mConfig *model
 		return "", err
 	}
 
-	var htmlTable strings.Builder
-	htmlTable.WriteString(`<link rel="stylesheet" href="/wedyta/static/css/wedyta.css">` + "\n")
-
-	if len(mConfig.EditableFields) > 0 {
-		htmlTable.WriteString(`
-<script src="https://code.jquery.com/jquery-3.7.1.min.js"></script>
-<script src="/wedyta/static/js/wedyta_update.js"></script>
-`)
+	breadcrumbs, err := s.optionalBreadcrumbs(mConfig, "", "read records", opts)
+	if err != nil {
+		return "", err
 	}
 
-	htmlTable.WriteString(`<` + s.Config.HeadersTag + `>` + mConfig.PageTitle + `</` + s.Config.HeadersTag + `>` + "\n")
-	htmlTable.WriteString(s.breadcrumbBuilder(mConfig, "", "read records"))
-
-	addForm := s.renderAddForm(ctx, mConfig, "refresh_page")
-	if addForm != "" {
-		htmlTable.WriteString(s.wrapBsAccordion(addForm, "", "Add New Record"))
+	data := tableData{
+		pageData: s.newPageData(mConfig, breadcrumbs),
+		Scripts:  !opts.HideScripts && hasEditingScripts(mConfig),
 	}
 
-	htmlTable.WriteString("<table class='table table-striped mt-3 table-model-records' model='" + mConfig.ModelName + "'>\n<thead>\n<tr>\n")
-
-	for _, field := range mConfig.Fields {
-		if !mConfig.FieldConfig[field].PermitDisplayInTableMode {
-			continue
+	if !opts.HideAddForm {
+		addForm, err := s.renderAddForm(ctx, mConfig, "refresh_page", nil)
+		if err != nil {
+			return "", err
 		}
-
-		header := mConfig.FieldConfig[field].Header
-
-		titleStr := ""
-		if title, ok := mConfig.Titles[field]; ok {
-			titleStr = fmt.Sprintf(" title='%s'", title)
+		if addForm != "" {
+			if data.AddForm, err = s.wrapBsAccordion(mConfig, addForm, "", s.translate(mConfig, "Add New Record")); err != nil {
+				return "", err
+			}
 		}
-
-		htmlTable.WriteString(fmt.Sprintf("<th%s id=\"header_of_%s\">%s</th>\n", titleStr, field, header))
 	}
-	htmlTable.WriteString("</tr>\n</thead>\n<tbody>\n")
 
 	//relatedDataCache := make(map[string]string)
 	var cache model.RenderTableCache
 	cache.RelatedData = make(map[string]string)
 
+	data.BulkActions, data.BulkFields = s.bulkActionsData(ctx, mConfig, &cache)
+
+	for _, field := range mConfig.Fields {
+		if !mConfig.FieldConfig[field].PermitDisplayInTableMode || !s.fieldPermitted(ctx, mConfig, field, "read", &cache) {
+			continue
+		}
+
+		data.Headers = append(data.Headers, headerData{Field: field, Title: mConfig.Titles[field], Header: template.HTML(mConfig.FieldConfig[field].Header)})
+	}
+
 	for _, record := range records {
-		trClass := ""
-		if !extractIsActive(record) {
-			trClass = ` class="disabled"`
+		row := rowData{Disabled: !extractIsActive(record)}
+		if value, exists := record[mConfig.DbTablePrimaryKey]; exists {
+			row.PK = fmt.Sprintf("%v", value)
+		}
+		if mConfig.VersionField != "" {
+			row.Version = formatRecordVersion(record[mConfig.VersionField])
 		}
 
-		htmlTable.WriteString("<tr" + trClass + ">\n")
 		for _, field := range mConfig.Fields {
-			if !mConfig.FieldConfig[field].PermitDisplayInTableMode {
+			if !mConfig.FieldConfig[field].PermitDisplayInTableMode || !s.fieldPermitted(ctx, mConfig, field, "read", &cache) {
 				continue
 			}
 
-			value, tagAttrs := s.renderRecordValue(ctx, mConfig, field, record, &cache)
-			htmlTable.WriteString(fmt.Sprintf("\t<td%s>%v</td>\n", tagAttrs, value))
+			_, cell := s.renderRecordValue(ctx, mConfig, field, record, &cache)
+			row.Cells = append(row.Cells, cell)
 		}
-		htmlTable.WriteString("</tr>\n")
+		data.Rows = append(data.Rows, row)
 	}
-	htmlTable.WriteString("</tbody>\n</table>")
 
-	curPageUrl := mConfig.ModelName + mConfig.AdditionalUrlParams
-	htmlTable.WriteString(s.buildPagination(totalRecords, s.Config.PaginationRecordsPerPage, pageNum, curPageUrl))
+	curPageUrl := "/wedyta/" + mConfig.ModelName + mConfig.AdditionalUrlParams
+	if data.Pagination, err = s.buildPagination(mConfig, totalRecords, s.Config.PaginationRecordsPerPage, pageNum, curPageUrl); err != nil {
+		return "", err
+	}
 
-	return htmlTable.String(), nil
+	htmlTable, err := s.renderTemplate(mConfig, "table", data)
+	return string(htmlTable), err
 }
 
-func (s *Service) buildPagination(totalRecords int64, pageSize int, pageNum int, url string) string {
+func (s *Service) buildPagination(mConfig *model.ConfigOfModel, totalRecords int64, pageSize int, pageNum int, url string) (template.HTML, error) {
 	pageCount := int((totalRecords + int64(pageSize) - 1) / int64(pageSize))
 	if pageCount < 2 {
-		return ""
+		return "", nil
 	}
 
 	const delta = 5
@@ -146,38 +151,35 @@ func (s *Service) buildPagination(totalRecords int64, pageSize int, pageNum int,
 		urlConnectionSign = "&"
 	}
 
-	pagination := "<nav aria-label=\"Page navigation\">\n<ul class=\"pagination justify-content-center\">\n"
+	pageUrl := func(i int) string {
+		if i == 1 {
+			return url
+		}
+		return fmt.Sprintf("%s%spage=%d", url, urlConnectionSign, i)
+	}
+
+	var pages []paginationPage
 
 	// ← First page
 	if start > 1 {
-		pagination += fmt.Sprintf("<li class=\"page-item\"><a class=\"page-link\" href=\"%s\">1</a></li>\n", url)
+		pages = append(pages, paginationPage{Number: 1, URL: pageUrl(1)})
 		if start > 2 {
-			pagination += "<li class=\"page-item disabled\"><span class=\"page-link\">...</span></li>\n"
+			pages = append(pages, paginationPage{Gap: true})
 		}
 	}
 
 	// ← Pages around current
 	for i := start; i <= end; i++ {
-		url_ := url
-		if i > 1 {
-			url_ += fmt.Sprintf("%spage=%d", urlConnectionSign, i)
-		}
-		active := ""
-		if i == pageNum {
-			active = " active"
-		}
-		pagination += fmt.Sprintf("<li class=\"page-item%s\"><a class=\"page-link\" href=\"%s\">%d</a></li>\n", active, url_, i)
+		pages = append(pages, paginationPage{Number: i, URL: pageUrl(i), Active: i == pageNum})
 	}
 
 	// → Last page
 	if end < pageCount {
 		if end < pageCount-1 {
-			pagination += "<li class=\"page-item disabled\"><span class=\"page-link\">...</span></li>\n"
+			pages = append(pages, paginationPage{Gap: true})
 		}
-		url_ := url + fmt.Sprintf("%spage=%d", urlConnectionSign, pageCount)
-		pagination += fmt.Sprintf("<li class=\"page-item\"><a class=\"page-link\" href=\"%s\">%d</a></li>\n", url_, pageCount)
+		pages = append(pages, paginationPage{Number: pageCount, URL: pageUrl(pageCount)})
 	}
 
-	pagination += "</ul>\n</nav>\n"
-	return pagination
+	return s.renderTemplate(mConfig, "pagination", paginationData{Pages: pages})
 }
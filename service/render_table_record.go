@@ -1,15 +1,15 @@
 package service
 
 import (
+	"errors"
 	"fmt"
-	"github.com/gin-gonic/gin"
-	"github.com/pa-pe/wedyta/model"
-	"log"
+	"html/template"
 	"strconv"
-	"strings"
+
+	"github.com/pa-pe/wedyta/model"
 )
 
-func (s *Service) RenderTableRecord(ctx *gin.Context) {
+func (s *Service) RenderTableRecord(ctx *model.Request) {
 	modelName := ctx.Param("modelName")
 	recIDstr := ctx.Param("recID")
 	recID, err := strconv.ParseInt(recIDstr, 10, 64)
@@ -41,9 +41,13 @@ func (s *Service) RenderTableRecord(ctx *gin.Context) {
 	s.RenderPage(ctx, mConfig, htmlTable)
 }
 
-func (s *Service) RenderModelTableRecord(ctx *gin.Context, mConfig *model.ConfigOfModel, recID int64, isUpdateMode bool) (string, error) {
+func (s *Service) RenderModelTableRecord(ctx *model.Request, mConfig *model.ConfigOfModel, recID int64, isUpdateMode bool) (string, error) {
+	return s.renderModelTableRecord(ctx, mConfig, recID, isUpdateMode, model.RenderOptions{})
+}
+
+func (s *Service) renderModelTableRecord(ctx *model.Request, mConfig *model.ConfigOfModel, recID int64, isUpdateMode bool, opts model.RenderOptions) (string, error) {
 	if mConfig == nil {
-		log.Fatalf("Wedyta: RenderModelTableRecord(): mConfig == nil")
+		return "", errors.New("RenderModelTableRecord(): mConfig == nil")
 	}
 
 	if mConfig.DbTablePrimaryKey == "" {
@@ -62,60 +66,48 @@ func (s *Service) RenderModelTableRecord(ctx *gin.Context, mConfig *model.Config
 		Table(mConfig.DbTable).
 		Where(fmt.Sprintf("%s = %d", mConfig.DbTablePrimaryKey, recID)).
 		Where(mConfig.SqlWhere).
+		Scopes(notSoftDeleted(mConfig)).
 		Take(&record).Error; err != nil {
 		return "", err
 	}
 
-	var htmlTable strings.Builder
-
-	if len(mConfig.EditableFields) > 0 {
-		htmlTable.WriteString(`
-` + s.Config.JQueryScriptTag + `
-<script src="/wedyta/static/js/wedyta_update.js"></script>
-` + mConfig.AdditionalScripts)
+	breadcrumbs, err := s.optionalBreadcrumbs(mConfig, recordLabel(mConfig, strconv.FormatInt(recID, 10), record), action, opts)
+	if err != nil {
+		return "", err
 	}
 
-	htmlTable.WriteString(`
-<style>
-table { width: auto !important; }
-th { white-space: nowrap; width: 55px; }
-td { width: auto !important; }
-.form-label { font-weight: bold; }
-</style>
-`)
-
-	htmlTable.WriteString("<div class=\"col\">\n")
+	fields := recordFieldsData{
+		pageData:   s.newPageData(mConfig, breadcrumbs),
+		UpdateMode: isUpdateMode,
+		VersionKey: versionPayloadKey,
+	}
 
-	htmlTable.WriteString(`<` + s.Config.HeadersTag + `>` + mConfig.PageTitle + `</` + s.Config.HeadersTag + `>` + "\n")
-	htmlTable.WriteString(s.breadcrumbBuilder(mConfig, fmt.Sprintf("%d", recID), action))
+	if mConfig.VersionField != "" {
+		fields.Version = formatRecordVersion(record[mConfig.VersionField])
+	}
 
-	var pkValue string
 	value, exists := record[mConfig.DbTablePrimaryKey]
 	if exists {
-		pkValue = fmt.Sprintf("%v", value)
+		fields.PK = fmt.Sprintf("%v", value)
 	}
-	if isUpdateMode {
-		if pkValue == "" {
-			s.SomethingWentWrong(ctx, "Can't take primary key value")
-		}
-
-		htmlTable.WriteString("<form id=\"editForm\">\n")
-		htmlTable.WriteString(" <input type=\"hidden\" name=\"modelName\" value=\"" + mConfig.ModelName + "\">\n")
-		htmlTable.WriteString("<input type=\"hidden\" name=\"id\" value=\"" + pkValue + "\">\n")
+	if isUpdateMode && fields.PK == "" {
+		s.SomethingWentWrong(ctx, "Can't take primary key value")
 	}
 
-	tblClass := "table-model-record"
-	if isUpdateMode {
-		tblClass += "-update"
-	}
-	htmlTable.WriteString("<table class='table table-striped mt-3 " + tblClass + "' model='" + mConfig.ModelName + "' record_id='" + pkValue + "'>\n<tbody>\n<tr>\n")
+	var cache model.RenderTableCache
+	cache.RelatedData = make(map[string]string)
 
 	for _, field := range mConfig.Fields {
 		fldCfg := mConfig.FieldConfig[field]
 
+		if !s.fieldPermitted(ctx, mConfig, field, "read", &cache) {
+			continue
+		}
+		fldCfg.IsEditable = fldCfg.IsEditable && s.fieldPermitted(ctx, mConfig, field, "update", &cache)
+
 		if isUpdateMode {
 			// skip stdRecordControls in isUpdateMode
-			if mConfig.ColumnDataFunc[field] == "stdRecordControls" {
+			if mConfig.ColumnDataFunc[field].Name == "stdRecordControls" {
 				continue
 			}
 
@@ -128,35 +120,53 @@ td { width: auto !important; }
 			}
 		}
 
-		header := fldCfg.Header
-
-		titleStr := ""
-		if fldCfg.Title != "" {
-			titleStr = fmt.Sprintf(" title='%s'", fldCfg.Title)
+		value, cell := s.renderRecordValue(ctx, mConfig, field, record, &cache)
+		recordField := recordFieldData{
+			cellData:   cell,
+			headerData: headerData{Field: field, Title: fldCfg.Title, Header: template.HTML(fldCfg.Header)},
+		}
+		if isUpdateMode && fldCfg.IsEditable {
+			if recordField.Label, recordField.Input, err = s.renderFormInputTag(&fldCfg, mConfig, record, value); err != nil {
+				return "", err
+			}
 		}
+		fields.Fields = append(fields.Fields, recordField)
+	}
 
-		var cache model.RenderTableCache
-		cache.RelatedData = make(map[string]string)
+	body, err := s.renderTemplate(mConfig, "record_fields", fields)
+	if err != nil {
+		return "", err
+	}
 
-		value, tagAttrs := s.renderRecordValue(ctx, mConfig, field, record, &cache)
-		if isUpdateMode && fldCfg.IsEditable {
-			labelTag, fieldTag := s.renderFormInputTag(&fldCfg, mConfig, record, value)
-			htmlTable.WriteString("<tr>\n <td" + tagAttrs + " colspan=\"2\">\n")
-			htmlTable.WriteString(labelTag + "<br>\n")
-			htmlTable.WriteString(fieldTag + "\n")
-			htmlTable.WriteString("</td>\n</tr>\n")
-		} else {
-			htmlTable.WriteString(fmt.Sprintf("<tr>\n <th%s id=\"header_of_%s\">%s</th>\n <td%s>%v</td>\n</tr>\n", titleStr, field, header, tagAttrs, value))
+	if s.Config.AuditEnabled && !isUpdateMode {
+		history, err := s.renderAuditHistory(ctx, mConfig, recID, &cache)
+		if err != nil {
+			return "", err
+		}
+		if body, err = s.wrapBsTabs(mConfig, "record", []tabData{{Title: s.translate(mConfig, "Record"), Content: body}, {Title: s.translate(mConfig, "History"), Content: history}}); err != nil {
+			return "", err
 		}
 	}
-	htmlTable.WriteString("</tbody>\n</table>\n")
 
-	if isUpdateMode {
-		htmlTable.WriteString("<button type=\"button\" class=\"btn btn-primary\" id=\"saveButton\">Update</button>\n")
-		htmlTable.WriteString("</form>\n")
+	scripts := hasEditingScripts(mConfig)
+	if len(mConfig.Children) > 0 && !isUpdateMode {
+		children, childScripts, err := s.renderChildTables(ctx, mConfig, recID)
+		if err != nil {
+			return "", err
+		}
+		body += children
+		scripts = scripts || childScripts
 	}
 
-	htmlTable.WriteString("</div>\n")
+	data := recordData{
+		pageData: fields.pageData,
+		Scripts:  scripts,
+		Body:     body,
+	}
+	if !isUpdateMode {
+		data.PrevURL, data.NextURL = s.recordNavigation(mConfig, recID, opts.Filters)
+	}
 
-	return htmlTable.String(), nil
+	htmlTable, err := s.renderTemplate(mConfig, "record", data)
+	return string(htmlTable), err
 }
@@ -1,15 +1,15 @@
 package service
 
 import (
+	"errors"
 	"fmt"
-	"github.com/gin-gonic/gin"
-	"github.com/pa-pe/wedyta/model"
-	"log"
+	"html/template"
 	"slices"
-	"strings"
+
+	"github.com/pa-pe/wedyta/model"
 )
 
-func (s *Service) RenderTableRecordCreate(ctx *gin.Context) {
+func (s *Service) RenderTableRecordCreate(ctx *model.Request) {
 	modelName := ctx.Param("modelName")
 	action := "create"
 
@@ -18,7 +18,7 @@ func (s *Service) RenderTableRecordCreate(ctx *gin.Context) {
 		return
 	}
 
-	htmlTable, err := s.renderModelTableRecordCreate(ctx, mConfig, action)
+	htmlTable, err := s.renderModelTableRecordCreate(ctx, mConfig, action, model.RenderOptions{})
 	if err != nil {
 		s.SomethingWentWrong(ctx, fmt.Sprintf("RenderModelTableRecord error: %v", err))
 		return
@@ -27,51 +27,50 @@ func (s *Service) RenderTableRecordCreate(ctx *gin.Context) {
 	s.RenderPage(ctx, mConfig, htmlTable)
 }
 
-func (s *Service) renderModelTableRecordCreate(ctx *gin.Context, mConfig *model.ConfigOfModel, action string) (string, error) {
+func (s *Service) renderModelTableRecordCreate(ctx *model.Request, mConfig *model.ConfigOfModel, action string, opts model.RenderOptions) (string, error) {
 	if mConfig == nil {
-		log.Fatalf("Wedyta: RenderModelTableRecord(): mConfig == nil")
+		return "", errors.New("RenderModelTableRecord(): mConfig == nil")
 	}
 
-	var htmlTable strings.Builder
-
-	htmlTable.WriteString("<div class=\"col\">\n")
-
-	htmlTable.WriteString(`<` + s.Config.HeadersTag + `>` + mConfig.PageTitle + `</` + s.Config.HeadersTag + `>` + "\n")
-	htmlTable.WriteString(s.breadcrumbBuilder(mConfig, "", action))
+	breadcrumbs, err := s.optionalBreadcrumbs(mConfig, "", action, opts)
+	if err != nil {
+		return "", err
+	}
 
-	htmlTable.WriteString(s.renderAddForm(ctx, mConfig, "show_record"))
+	prefill, err := s.duplicatePrefill(ctx, mConfig)
+	if err != nil {
+		return "", err
+	}
 
-	htmlTable.WriteString("</div>\n")
+	addForm, err := s.renderAddForm(ctx, mConfig, "show_record", prefill)
+	if err != nil {
+		return "", err
+	}
 
-	return htmlTable.String(), nil
+	htmlTable, err := s.renderTemplate(mConfig, "create", createData{pageData: s.newPageData(mConfig, breadcrumbs), AddForm: addForm})
+	return string(htmlTable), err
 }
 
-func (s *Service) renderAddForm(ctx *gin.Context, mConfig *model.ConfigOfModel, successfullyCreatedDestination string) string {
+// renderAddForm renders the form with the values of the prefill, e.g. of the duplicated record, overridden by the query values
+func (s *Service) renderAddForm(ctx *model.Request, mConfig *model.ConfigOfModel, successfullyCreatedDestination string, prefill map[string]string) (template.HTML, error) {
 	if mConfig == nil || len(mConfig.AddableFields) == 0 {
-		return ""
+		return "", nil
 	}
 
-	var formBuilder strings.Builder
-	formBuilder.WriteString(`
-` + s.Config.JQueryScriptTag + `
-<script src="/wedyta/static/js/wedyta_create.js"></script>
-<link rel="stylesheet" href="/wedyta/static/css/wedyta_create.css">
-` + mConfig.AdditionalScripts)
-
-	formBuilder.WriteString(fmt.Sprintf(`<form id="addForm">
-        <input type="hidden" name="modelName" value="%s">`+"\n", mConfig.ModelName))
+	data := addFormData{
+		pageData:    s.newPageData(mConfig, ""),
+		Destination: successfullyCreatedDestination,
+	}
 
 	// adding a linking field to the parent table
 	if mConfig.Parent.QueryVariableName != "" && mConfig.Parent.QueryVariableValue != "" {
 		// adding input type="hidden" just if input type="text" not present
 		if slices.Contains(mConfig.AddableFields, mConfig.Parent.QueryVariableName) == false {
-			formBuilder.WriteString(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`+"\n", mConfig.Parent.QueryVariableName, mConfig.Parent.QueryVariableValue))
+			data.ParentField = mConfig.Parent.QueryVariableName
+			data.ParentValue = mConfig.Parent.QueryVariableValue
 		}
 	}
 
-	formBuilder.WriteString(`<input type="hidden" name="successfullyCreatedDestination" value="` + successfullyCreatedDestination + `">` + "\n")
-
-	countFields := 0
 	for _, field := range mConfig.AddableFields {
 		fldCfg := mConfig.FieldConfig[field]
 
@@ -83,27 +82,22 @@ func (s *Service) renderAddForm(ctx *gin.Context, mConfig *model.ConfigOfModel,
 			continue
 		}
 
-		value := ""
+		value := prefill[fldCfg.Field]
 		if val, exist := ctx.GetQuery(fldCfg.Field); exist {
 			value = val
 		}
-		fmt.Printf("%s='%s'\n", fldCfg.Field, value)
-
-		labelTag, fieldTag := s.renderFormInputTag(&fldCfg, mConfig, nil, value)
 
-		formBuilder.WriteString("<div class=\"mb-3\">\n")
-		formBuilder.WriteString(labelTag + "\n")
-		formBuilder.WriteString(fieldTag + "\n")
-		formBuilder.WriteString("</div>\n")
-
-		countFields++
+		labelTag, fieldTag, err := s.renderFormInputTag(&fldCfg, mConfig, nil, value)
+		if err != nil {
+			return "", err
+		}
+		data.Fields = append(data.Fields, formFieldData{Label: labelTag, Input: fieldTag})
 	}
 
 	// skip return add form if no addable fields by AccessCheckFunc or no PermitDisplayInInsertMode
-	if countFields == 0 {
-		return ""
+	if len(data.Fields) == 0 {
+		return "", nil
 	}
 
-	formBuilder.WriteString(`<button type="submit" class="btn btn-primary">Create</button>` + "\n</form>\n")
-	return formBuilder.String()
+	return s.renderTemplate(mConfig, "add_form", data)
 }
@@ -1,24 +1,21 @@
 package service
 
-import "strings"
+import (
+	"html/template"
 
-func (s *Service) wrapBsAccordion(content, idPrefix, header string) string {
-	var formBuilder strings.Builder
+	"github.com/pa-pe/wedyta/model"
+)
 
-	formBuilder.WriteString(`
-	<div class="accordion" id="` + idPrefix + `Accordion">
-        <div class="accordion-item">
-            <` + s.Config.HeadersTag + ` class="accordion-header" id="` + idPrefix + `Heading">
-                <button class="accordion-button collapsed" type="button" data-bs-toggle="collapse" data-bs-target="#` + idPrefix + `Collapse" aria-expanded="false" aria-controls="` + idPrefix + `Collapse">
-                    <i class="bi-plus-square"></i> &nbsp; ` + header + `
-                </button>
-            </` + s.Config.HeadersTag + `>
-            <div id="` + idPrefix + `Collapse" class="accordion-collapse collapse" aria-labelledby="` + idPrefix + `Heading" data-bs-parent="#` + idPrefix + `Accordion">
-                <div class="accordion-body" style="background: rgba(128,128,128,0.1);">
-`)
+func (s *Service) wrapBsAccordion(mConfig *model.ConfigOfModel, content template.HTML, idPrefix, header string) (template.HTML, error) {
+	return s.renderTemplate(mConfig, "accordion", accordionData{ID: idPrefix, Header: header, Content: content})
+}
 
-	formBuilder.WriteString(content)
-	formBuilder.WriteString("</div>\n</div>\n</div>\n")
+// hasEditingScripts reports whether the tables of the model need the scripts of in place editing and deleting
+func hasEditingScripts(mConfig *model.ConfigOfModel) bool {
+	return len(mConfig.EditableFields) > 0 || mConfig.Deletable || len(mConfig.BulkActions) > 0 || len(mConfig.RecordActions) > 0
+}
 
-	return formBuilder.String()
+// wrapBsTabs renders the contents as bootstrap tabs, the first tab is active
+func (s *Service) wrapBsTabs(mConfig *model.ConfigOfModel, idPrefix string, tabs []tabData) (template.HTML, error) {
+	return s.renderTemplate(mConfig, "tabs", tabsData{ID: idPrefix, Tabs: tabs})
 }
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pa-pe/wedyta/embed"
+	"github.com/pa-pe/wedyta/model"
+)
+
+// StaticPath is the URL prefix of the embedded scripts, styles and vendored assets, see StaticFiles
+const StaticPath = "/wedyta/static"
+
+// Route is an endpoint of the wedyta UI served by the adapters,
+// the parameters of Path are written as {name}, e.g. /wedyta/{modelName}
+type Route struct {
+	Method  string
+	Path    string
+	Handler func(ctx *model.Request)
+}
+
+// Routes returns the endpoints of the wedyta UI, the static files and the uploads are served by the adapters
+func (s *Service) Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/wedyta/", s.RenderIndex},
+		{http.MethodGet, "/wedyta/{modelName}", s.RenderTable},
+		{http.MethodGet, "/wedyta/{modelName}/create", s.RenderTableRecordCreate},
+		{http.MethodGet, "/wedyta/{modelName}/trash", s.RenderTrash},
+		{http.MethodGet, "/wedyta/{modelName}/{recID}", s.RenderTableRecord},
+		{http.MethodGet, "/wedyta/{modelName}/{recID}/{action}", s.routeModelRecordAction},
+		{http.MethodPost, "/wedyta/create", s.HandleTableCreateRecord},
+		{http.MethodPost, "/wedyta/update", s.Update},
+		{http.MethodPost, "/wedyta/delete", s.Delete},
+		{http.MethodPost, "/wedyta/bulk", s.Bulk},
+		{http.MethodPost, "/wedyta/action", s.HandleRecordAction},
+		{http.MethodPost, "/wedyta/restore", s.Restore},
+		{http.MethodPost, "/wedyta/purge", s.Purge},
+		{http.MethodPost, "/wedyta/upload/check", s.HandleUploadCheck},
+		{http.MethodPost, "/wedyta/upload/image", s.HandleImageUpload},
+	}
+}
+
+func (s *Service) routeModelRecordAction(ctx *model.Request) {
+	action := ctx.Param("action")
+
+	switch action {
+	case "update":
+		s.RenderTableRecord(ctx)
+	default:
+		s.SomethingWentWrong(ctx, "RouteModelRecordAction: unknown action="+action)
+	}
+}
+
+// StaticFiles returns the embedded files served under StaticPath
+func StaticFiles() fs.FS {
+	staticFiles, err := fs.Sub(embed.EmbeddedFiles, "static")
+	if err != nil {
+		log.Fatalf("failed to initialize static files: %v", err)
+	}
+	return staticFiles
+}
+
+// Params returns the names of the parameters of the route path
+func (r Route) Params() []string {
+	var names []string
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+	}
+	return names
+}
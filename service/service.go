@@ -1,32 +1,63 @@
 package service
 
 import (
-	"github.com/gin-gonic/gin"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"sync"
+
 	"github.com/pa-pe/wedyta/model"
+	"golang.org/x/text/language"
 	"gorm.io/gorm"
 )
 
 type Service struct {
 	DB                *gorm.DB
 	Config            *model.WedytaConfig
+	cacheMu           sync.RWMutex // guards modelCache and unavailableModels
 	modelCache        map[string]model.CachedModelConfig
+	unavailableModels map[string]model.ConfigIssues
+	watcher           *configWatcher
+	templates         *template.Template                  // guarded by cacheMu
+	templatesByLocale map[language.Tag]*template.Template // clones of templates translating by t, guarded by cacheMu
+	assets            map[string]assetRef
+	i18n              *localeCatalog
+	recordActionsMu   sync.RWMutex // guards recordActions
+	recordActions     map[string]model.RecordAction
+	columnFuncsMu     sync.RWMutex // guards columnFuncs
+	columnFuncs       map[string]model.ColumnFunc
 	UploadsConfigured bool
 }
 
+// NewService is NewServiceE panicking on error
 func NewService(db *gorm.DB, wedytaConfig *model.WedytaConfig) *Service {
+	s, err := NewServiceE(db, wedytaConfig)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewServiceE sets the defaults of the config and creates the service, it fails if the translations or the templates
+// can't be loaded or the model configs have issues in the "strict" ConfigValidation
+func NewServiceE(db *gorm.DB, wedytaConfig *model.WedytaConfig) (*Service, error) {
 	if wedytaConfig == nil {
 		// default if no wedytaConfig
-		wedytaConfig = &model.WedytaConfig{
-			AccessCheckFunc: func(context *gin.Context, modelName, action, fieldName string) bool {
-				return true // default permit all
-			},
-		}
+		wedytaConfig = &model.WedytaConfig{}
 	}
 
 	if wedytaConfig.ConfigDir == "" {
 		wedytaConfig.ConfigDir = "config/wedyta"
 	}
 
+	if wedytaConfig.ConfigFS == nil {
+		wedytaConfig.ConfigFS = os.DirFS(wedytaConfig.ConfigDir)
+	} else if wedytaConfig.WatchConfigDir && wedytaConfig.ConfigFS != os.DirFS(wedytaConfig.ConfigDir) {
+		return nil, errors.New("WeDyTa: WatchConfigDir watches ConfigDir, it can't be used with a custom ConfigFS")
+	}
+
 	if wedytaConfig.HeadersTag == "" {
 		wedytaConfig.HeadersTag = "h2"
 	}
@@ -35,6 +66,14 @@ func NewService(db *gorm.DB, wedytaConfig *model.WedytaConfig) *Service {
 		wedytaConfig.PaginationRecordsPerPage = 100
 	}
 
+	if wedytaConfig.DefaultLocale == "" {
+		wedytaConfig.DefaultLocale = "en"
+	}
+
+	if wedytaConfig.IndexPageTitle == "" {
+		wedytaConfig.IndexPageTitle = "Models"
+	}
+
 	if wedytaConfig.BreadcrumbsRootName == "" {
 		wedytaConfig.BreadcrumbsRootName = "Home"
 	}
@@ -51,22 +90,76 @@ func NewService(db *gorm.DB, wedytaConfig *model.WedytaConfig) *Service {
 		db = db.Debug()
 	}
 
+	if wedytaConfig.AssetsSource == "" {
+		wedytaConfig.AssetsSource = model.AssetsSourceEmbedded
+	}
+	assets, err := resolveAssets(wedytaConfig.AssetsSource)
+	if err != nil {
+		return nil, err
+	}
+
 	if wedytaConfig.JQueryScriptTag == "" {
-		wedytaConfig.JQueryScriptTag = "<script src=\"https://code.jquery.com/jquery-3.7.1.min.js\"></script>"
+		wedytaConfig.JQueryScriptTag = string(assetTag(assets, "jquery.js"))
 	}
 
 	if wedytaConfig.SummernoteInitTags == "" {
-		wedytaConfig.SummernoteInitTags = `
-<link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.css">
-<script src="https://cdnjs.cloudflare.com/ajax/libs/summernote/0.9.1/summernote-bs5.min.js"></script>
-`
+		wedytaConfig.SummernoteInitTags = "\n" + string(assetTag(assets, "summernote.css")) + "\n" + string(assetTag(assets, "summernote.js")) + "\n"
 	}
 	wedytaConfig.SummernoteInitTags += "<script src=\"/wedyta/static/js/wedyta_init_summernote.js\"></script>\n"
 
-	return &Service{
+	if wedytaConfig.AuditEnabled {
+		if err := db.AutoMigrate(&model.AuditRecord{}); err != nil {
+			log.Printf("WeDyTa: can't migrate audit table: %v", err)
+		}
+	}
+
+	s := &Service{
 		DB:                db,
 		Config:            wedytaConfig,
 		modelCache:        make(map[string]model.CachedModelConfig),
-		UploadsConfigured: false,
+		assets:            assets,
+		UploadsConfigured: wedytaConfig.FileUploadFolder != "" && wedytaConfig.FileUploadRelativePath != "",
 	}
+
+	i18n, err := newLocaleCatalog(wedytaConfig.DefaultLocale, wedytaConfig.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("WeDyTa: can't load translations: %w", err)
+	}
+	s.i18n = i18n
+
+	templates, err := s.parseTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("WeDyTa: can't parse templates: %w", err)
+	}
+	s.templates = templates
+	s.templatesByLocale = make(map[language.Tag]*template.Template)
+
+	for name, action := range wedytaConfig.RecordActions {
+		if err := s.RegisterRecordAction(name, action); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, fn := range wedytaConfig.ColumnFuncs {
+		if err := s.RegisterColumnFunc(name, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	if wedytaConfig.AccessCheckFunc == nil {
+		// default: evaluate the "permissions" matrix of the model config, permit all if it's absent
+		wedytaConfig.AccessCheckFunc = s.defaultAccessCheck
+	}
+
+	if err := s.validateConfigs(); err != nil {
+		return nil, fmt.Errorf("WeDyTa: refusing to start with invalid model configs:\n%w", err)
+	}
+
+	if wedytaConfig.WatchConfigDir {
+		if err := s.watchConfigDir(); err != nil {
+			log.Printf("WeDyTa: can't watch %s, the configs are checked on every request: %v", wedytaConfig.ConfigDir, err)
+		}
+	}
+
+	return s, nil
 }
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"gorm.io/gorm"
+)
+
+// notSoftDeleted is a gorm scope which hides soft deleted records of the model
+func notSoftDeleted(mConfig *model.ConfigOfModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !mConfig.SoftDelete.Enabled() {
+			return db
+		}
+		return db.Where(fmt.Sprintf("%s IS NULL", mConfig.SoftDelete.Field))
+	}
+}
+
+// onlySoftDeleted is a gorm scope which selects the records of the trash
+func onlySoftDeleted(mConfig *model.ConfigOfModel) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(fmt.Sprintf("%s IS NOT NULL", mConfig.SoftDelete.Field))
+	}
+}
+
+func (s *Service) RenderTrash(ctx *model.Request) {
+	modelName := ctx.Param("modelName")
+
+	action := "read"
+	permit, mConfig := s.checkAccessAndLoadModelConfig(ctx, modelName, action)
+	if !permit {
+		return
+	}
+
+	if !mConfig.SoftDelete.Enabled() {
+		ctx.String(http.StatusNotFound, "Trash is not enabled for model "+modelName)
+		return
+	}
+
+	htmlTable, err := s.renderModelTrash(ctx, mConfig)
+	if err != nil {
+		s.SomethingWentWrong(ctx, fmt.Sprintf("renderModelTrash error: %v", err))
+		return
+	}
+
+	s.RenderPage(ctx, mConfig, htmlTable)
+}
+
+func (s *Service) renderModelTrash(ctx *model.Request, mConfig *model.ConfigOfModel) (string, error) {
+	pageNum, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+
+	offset := (pageNum - 1) * s.Config.PaginationRecordsPerPage
+
+	totalRecords, err := sqlutils.GetTotalRecords(s.DB.Scopes(onlySoftDeleted(mConfig)), mConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var records []map[string]interface{}
+	if err := s.DB.
+		Table(mConfig.DbTable).
+		Where(mConfig.SqlWhere).
+		Scopes(onlySoftDeleted(mConfig)).
+		Order(mConfig.SoftDelete.Field + " DESC").
+		Limit(s.Config.PaginationRecordsPerPage).
+		Offset(offset).
+		Find(&records).Error; err != nil {
+		return "", err
+	}
+
+	breadcrumbs, err := s.breadcrumbBuilder(mConfig, "", "trash")
+	if err != nil {
+		return "", err
+	}
+
+	var cache model.RenderTableCache
+	cache.RelatedData = make(map[string]string)
+	cache.ReadOnly = true
+
+	data := trashData{
+		pageData:      s.newPageData(mConfig, breadcrumbs),
+		PermitRestore: s.fieldPermitted(ctx, mConfig, "", "restore", &cache),
+		PermitPurge:   s.fieldPermitted(ctx, mConfig, "", "purge", &cache),
+	}
+
+	var fields []string
+	for _, field := range mConfig.Fields {
+		if !mConfig.FieldConfig[field].PermitDisplayInTableMode || mConfig.ColumnDataFunc[field].Name == "stdRecordControls" {
+			continue
+		}
+		if !s.fieldPermitted(ctx, mConfig, field, "read", &cache) {
+			continue
+		}
+		fields = append(fields, field)
+		data.Headers = append(data.Headers, headerData{Field: field, Header: template.HTML(mConfig.FieldConfig[field].Header)})
+	}
+
+	for _, record := range records {
+		row := rowData{
+			Disabled:  true,
+			DeletedAt: sqlutils.ExtractFormattedTime(record[mConfig.SoftDelete.Field], "2006-01-02 15:04:05"),
+		}
+		if value, exists := record[mConfig.DbTablePrimaryKey]; exists {
+			row.PK = fmt.Sprintf("%v", value)
+		}
+
+		for _, field := range fields {
+			_, cell := s.renderRecordValue(ctx, mConfig, field, record, &cache)
+			row.Cells = append(row.Cells, cell)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	curPageUrl := "trash" + mConfig.AdditionalUrlParams
+	if data.Pagination, err = s.buildPagination(mConfig, totalRecords, s.Config.PaginationRecordsPerPage, pageNum, curPageUrl); err != nil {
+		return "", err
+	}
+
+	htmlTable, err := s.renderTemplate(mConfig, "trash", data)
+	return string(htmlTable), err
+}
+
+// Restore returns the soft deleted record from the trash
+func (s *Service) Restore(ctx *model.Request) {
+	mConfig, id, ok := s.takeRecordActionPayload(ctx, "restore")
+	if !ok {
+		return
+	}
+
+	if !mConfig.SoftDelete.Enabled() {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Trash is not enabled for this model"})
+		return
+	}
+
+	originalData := make(map[string]interface{})
+	if err := s.DB.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(onlySoftDeleted(mConfig)).Select(mConfig.SoftDelete.Field).Take(&originalData).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, model.H{"error": "Record not found in trash"})
+		return
+	}
+
+	changes := auditChanges(mConfig, originalData, nil)
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Update(mConfig.SoftDelete.Field, nil).Error; err != nil {
+			log.Printf("Wedyta: Failed to restore record, error: %v", err)
+			return newHttpError(http.StatusInternalServerError, "Failed to restore record")
+		}
+
+		return s.writeAudit(ctx, tx, mConfig, id, "restore", changes)
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	s.fireAfterCommit(ctx, mConfig, "restore", id, changes)
+
+	ctx.JSON(http.StatusOK, model.H{"success": true, "message": "Record restored successfully"})
+}
+
+// Purge permanently deletes the soft deleted record
+func (s *Service) Purge(ctx *model.Request) {
+	mConfig, id, ok := s.takeRecordActionPayload(ctx, "purge")
+	if !ok {
+		return
+	}
+
+	if !mConfig.SoftDelete.Enabled() {
+		ctx.JSON(http.StatusBadRequest, model.H{"error": "Trash is not enabled for this model"})
+		return
+	}
+
+	originalData := make(map[string]interface{})
+	if err := s.DB.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Where(mConfig.SqlWhere).Scopes(onlySoftDeleted(mConfig)).Take(&originalData).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, model.H{"error": "Record not found in trash"})
+		return
+	}
+
+	changes := auditChanges(mConfig, originalData, nil)
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.deleteRecord(ctx, tx, mConfig, id, "purge", changes)
+	})
+	if err != nil {
+		respondTransactionError(ctx, err)
+		return
+	}
+
+	s.fireAfterCommit(ctx, mConfig, "purge", id, changes)
+
+	ctx.JSON(http.StatusOK, model.H{"success": true, "message": "Record deleted permanently"})
+}
+
+// softDeleteRecord sets the softDelete column of the record instead of removing the row
+func (s *Service) softDeleteRecord(ctx *model.Request, tx *gorm.DB, mConfig *model.ConfigOfModel, id int64) ([]model.FieldChange, error) {
+	deletedAt := time.Now()
+	if err := tx.Table(mConfig.DbTable).Where(fmt.Sprintf("%s = ?", mConfig.DbTablePrimaryKey), id).Update(mConfig.SoftDelete.Field, deletedAt).Error; err != nil {
+		log.Printf("Wedyta: Failed to soft delete record, error: %v", err)
+		return nil, newHttpError(http.StatusInternalServerError, "Failed to delete record")
+	}
+
+	changes := auditChanges(mConfig, nil, map[string]interface{}{mConfig.SoftDelete.Field: deletedAt.Format("2006-01-02 15:04:05")})
+	return changes, s.writeAudit(ctx, tx, mConfig, id, "delete", changes)
+}
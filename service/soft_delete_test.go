@@ -0,0 +1,68 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+func TestSoftDelete(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs.json": {Data: []byte(`{"fields": ["id", "name"], "deletable": true, "softDelete": true}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`CREATE TABLE docs (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, deleted_at DATETIME);
+		INSERT INTO docs (name) VALUES ('draft'), ('final')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	post := func(handler func(*model.Request), payload string) int {
+		ctx := model.NewRequest(httptest.NewRequest("POST", "/", strings.NewReader(payload)), nil)
+		handler(ctx)
+		return ctx.Response.Status
+	}
+	render := func(handler func(*model.Request), path string) string {
+		ctx := model.NewRequest(httptest.NewRequest("GET", path, nil), map[string]string{"modelName": "docs"})
+		handler(ctx)
+		return string(ctx.Response.Body)
+	}
+	rows := func() int64 {
+		var count int64
+		if err := s.DB.Table("docs").Count(&count).Error; err != nil {
+			t.Fatalf("failed to count: %v", err)
+		}
+		return count
+	}
+
+	if status := post(s.Delete, `{"modelName": "docs", "id": 1}`); status != http.StatusOK || rows() != 2 {
+		t.Fatalf("expected the record to be moved to the trash, got %d, rows: %d", status, rows())
+	}
+	if table := render(s.RenderTable, "/wedyta/docs"); strings.Contains(table, "draft") || !strings.Contains(table, "final") {
+		t.Errorf("expected the deleted record to be hidden in\n%s", table)
+	}
+	if trash := render(s.RenderTrash, "/wedyta/docs/trash"); !strings.Contains(trash, "draft") || strings.Contains(trash, "final") {
+		t.Errorf("expected the deleted record in the trash\n%s", trash)
+	}
+	if status := post(s.Purge, `{"modelName": "docs", "id": 2}`); status != http.StatusNotFound || rows() != 2 {
+		t.Errorf("expected the record outside of the trash not to be purged, got %d", status)
+	}
+
+	if status := post(s.Restore, `{"modelName": "docs", "id": 1}`); status != http.StatusOK {
+		t.Errorf("restore failed: %d", status)
+	}
+	if table := render(s.RenderTable, "/wedyta/docs"); !strings.Contains(table, "draft") {
+		t.Errorf("expected the restored record in\n%s", table)
+	}
+
+	post(s.Delete, `{"modelName": "docs", "id": 1}`)
+	if status := post(s.Purge, `{"modelName": "docs", "id": 1}`); status != http.StatusOK || rows() != 1 {
+		t.Errorf("expected the record to be purged, got %d, rows: %d", status, rows())
+	}
+	if status := post(s.Restore, `{"modelName": "docs", "id": 1}`); status != http.StatusNotFound {
+		t.Errorf("expected the purged record not to be restored, got %d", status)
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"html"
+	"html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/pa-pe/wedyta/embed"
+	"github.com/pa-pe/wedyta/model"
+	"golang.org/x/text/language"
+)
+
+// layoutTemplate is the page layout of the default templates, used unless WedytaConfig.Template is set
+const layoutTemplate = "default.tmpl"
+
+// overridableTemplates are the templates a model config may replace by the "templates" key
+var overridableTemplates = []string{
+	"table", "record", "record_fields", "create", "add_form", "form_label", "form_input", "select",
+	"trash", "history", "breadcrumbs", "pagination", "accordion", "tabs", "i18n", layoutTemplate,
+}
+
+// pageData is the data common to the page templates
+type pageData struct {
+	Config            *model.WedytaConfig
+	Model             *model.ConfigOfModel
+	Messages          map[string]string // translations of the scripts
+	Breadcrumbs       template.HTML
+	JQueryScriptTag   template.HTML
+	AdditionalScripts template.HTML
+}
+
+// cellData is a table cell of a record value
+type cellData struct {
+	Class     string
+	FieldName string
+	Value     template.HTML
+}
+
+type headerData struct {
+	Field  string
+	Title  string
+	Header template.HTML
+}
+
+type rowData struct {
+	PK        string
+	Disabled  bool
+	Version   string
+	DeletedAt string
+	Cells     []cellData
+}
+
+// bulkActionData is an action of the bulk actions menu of the table
+type bulkActionData struct {
+	Name  string
+	Title string
+}
+
+type tableData struct {
+	pageData
+	Scripts     bool
+	AddForm     template.HTML
+	BulkActions []bulkActionData
+	BulkFields  []headerData // fields of the setField bulk action
+	Headers     []headerData
+	Rows        []rowData
+	Pagination  template.HTML
+}
+
+type trashData struct {
+	pageData
+	Headers       []headerData
+	Rows          []rowData
+	PermitRestore bool
+	PermitPurge   bool
+	Pagination    template.HTML
+}
+
+type recordData struct {
+	pageData
+	Scripts bool
+	Body    template.HTML
+	PrevURL string // URL of the previous record of the table, empty on the first one
+	NextURL string
+}
+
+// recordFieldData is a value of the record page, or the form field in update mode if Input is set
+type recordFieldData struct {
+	cellData
+	headerData
+	Label template.HTML
+	Input template.HTML
+}
+
+type recordFieldsData struct {
+	pageData
+	UpdateMode bool
+	PK         string
+	Version    string
+	VersionKey string
+	Fields     []recordFieldData
+}
+
+type createData struct {
+	pageData
+	AddForm template.HTML
+}
+
+type formFieldData struct {
+	Label template.HTML
+	Input template.HTML
+}
+
+type addFormData struct {
+	pageData
+	ParentField string
+	ParentValue string
+	Destination string
+	Fields      []formFieldData
+}
+
+type formLabelData struct {
+	Field    string
+	Title    string
+	Header   template.HTML
+	Required bool
+}
+
+type formInputData struct {
+	Field    string
+	Editor   string
+	Value    string
+	Required bool
+	Editable bool
+	Checked  bool
+	PK       string
+	Select   template.HTML
+}
+
+type selectOption struct {
+	Value    string
+	Text     string
+	Selected bool
+}
+
+type selectData struct {
+	Field    string
+	Required bool
+	Options  []selectOption
+}
+
+type breadcrumbItem struct {
+	Title   string
+	URL     string
+	Current bool
+	Last    bool
+}
+
+type breadcrumbsData struct {
+	Divider string
+	Items   []breadcrumbItem
+}
+
+type paginationPage struct {
+	Number int
+	URL    string
+	Active bool
+	Gap    bool
+}
+
+type paginationData struct {
+	Pages []paginationPage
+}
+
+type accordionData struct {
+	ID      string
+	Header  string
+	Content template.HTML
+}
+
+type tabData struct {
+	Title   string
+	Content template.HTML
+}
+
+type tabsData struct {
+	ID   string
+	Tabs []tabData
+}
+
+type historyChange struct {
+	Header string
+	Before string
+	After  string
+}
+
+type historyRecord struct {
+	Date    string
+	User    string
+	Action  string
+	Changes []historyChange
+}
+
+type historyData struct {
+	Error   bool
+	Records []historyRecord
+}
+
+type indexEntryData struct {
+	ModelName string
+	Title     string
+	Count     int64
+}
+
+type indexGroupData struct {
+	Name    string
+	Entries []indexEntryData
+}
+
+type indexData struct {
+	Title  string
+	Groups []indexGroupData
+}
+
+// templateFuncs are the functions of the templates, the tag of the headings is WedytaConfig.HeadersTag, t translates the UI text, asset renders the tag of a frontend dependency of AssetsSource
+func (s *Service) templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"heading": func(title string) template.HTML {
+			return template.HTML("<" + s.Config.HeadersTag + ">" + html.EscapeString(title) + "</" + s.Config.HeadersTag + ">")
+		},
+		"headingOpen": func(class, id string) template.HTML {
+			return template.HTML("<" + s.Config.HeadersTag + ` class="` + html.EscapeString(class) + `" id="` + html.EscapeString(id) + `">`)
+		},
+		"headingClose": func() template.HTML {
+			return template.HTML("</" + s.Config.HeadersTag + ">")
+		},
+		"asset": func(name string) template.HTML {
+			return assetTag(s.assets, name)
+		},
+		// replaced by the translation to the locale of the request, see localizedTemplates
+		"t": func(text string, args ...interface{}) string {
+			return s.i18n.translate(language.Und, text, args...)
+		},
+	}
+	for name, fn := range s.Config.TemplateFuncs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// parseTemplates parses the default templates and the ones of WedytaConfig.TemplatesFS replacing them
+func (s *Service) parseTemplates() (*template.Template, error) {
+	templates, err := template.New("wedyta").Funcs(s.templateFuncs()).ParseFS(embed.EmbeddedFiles, embed.TemplatesPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Config.TemplatesFS != nil {
+		if names, _ := fs.Glob(s.Config.TemplatesFS, "*.tmpl"); len(names) > 0 {
+			if templates, err = templates.ParseFS(s.Config.TemplatesFS, "*.tmpl"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return templates, nil
+}
+
+// renderTemplate executes the template by its default name, or the one the model config uses instead
+func (s *Service) renderTemplate(mConfig *model.ConfigOfModel, name string, data interface{}) (template.HTML, error) {
+	if mConfig != nil && mConfig.Templates[name] != "" {
+		name = mConfig.Templates[name]
+	}
+
+	templates, err := s.localizedTemplates(modelLocale(mConfig))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return template.HTML(b.String()), nil
+}
+
+// localizedTemplates returns the clone of the templates translating to the locale, cloned once per locale
+func (s *Service) localizedTemplates(locale language.Tag) (*template.Template, error) {
+	s.cacheMu.RLock()
+	templates, found := s.templatesByLocale[locale]
+	parsed := s.templates
+	s.cacheMu.RUnlock()
+	if found {
+		return templates, nil
+	}
+
+	templates, err := parsed.Clone()
+	if err != nil {
+		return nil, err
+	}
+	templates.Funcs(template.FuncMap{
+		"t": func(text string, args ...interface{}) string {
+			return s.i18n.translate(locale, text, args...)
+		},
+	})
+
+	s.cacheMu.Lock()
+	// the templates may be parsed again by Reload meanwhile
+	if s.templates == parsed {
+		s.templatesByLocale[locale] = templates
+	}
+	s.cacheMu.Unlock()
+
+	return templates, nil
+}
+
+func (s *Service) newPageData(mConfig *model.ConfigOfModel, breadcrumbs template.HTML) pageData {
+	return pageData{
+		Config:            s.Config,
+		Model:             mConfig,
+		Messages:          s.i18n.scriptMessages(mConfig.Locale),
+		Breadcrumbs:       breadcrumbs,
+		JQueryScriptTag:   template.HTML(s.Config.JQueryScriptTag),
+		AdditionalScripts: template.HTML(mConfig.AdditionalScripts),
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTemplates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT); INSERT INTO items (name) VALUES ('apple');`).Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	s := NewService(db, &model.WedytaConfig{
+		AssetsSource: model.AssetsSourceCDN,
+		ConfigFS: fstest.MapFS{
+			"items.json":   {Data: []byte(`{"fields": ["id", "name"]}`)},
+			"compact.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name"], "templates": {"table": "compact_table"}}`)},
+			"broken.json":  {Data: []byte(`{"dbTable": "items", "fields": ["id"], "templates": {"grid": "compact_table", "record": "missing"}}`)},
+		},
+		TemplatesFS: fstest.MapFS{
+			"theme.tmpl": {Data: []byte(`{{define "breadcrumbs"}}<nav class="theme">{{range .Items}}{{shout .Title}};{{end}}</nav>{{end}}` +
+				`{{define "compact_table"}}<ul>{{range .Rows}}<li>{{(index .Cells 1).Value}}</li>{{end}}</ul>{{end}}`)},
+		},
+		TemplateFuncs: map[string]any{"shout": strings.ToUpper},
+	})
+
+	render := func(modelName string) string {
+		ctx := model.NewRequest(httptest.NewRequest("GET", "/wedyta/"+modelName, nil), map[string]string{"modelName": modelName})
+		s.RenderTable(ctx)
+		return string(ctx.Response.Body)
+	}
+
+	body := render("items")
+	for _, expected := range []string{`<nav class="theme">HOME;ITEMS;</nav>`, `<td>apple</td>`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("items: expected %q in\n%s", expected, body)
+		}
+	}
+
+	body = render("compact")
+	if !strings.Contains(body, `<ul><li>apple</li></ul>`) {
+		t.Errorf("compact: expected the table of the model template in\n%s", body)
+	}
+	if strings.Contains(body, "<table") {
+		t.Errorf("compact: unexpected default table in\n%s", body)
+	}
+
+	expected := map[string]bool{
+		`broken.json:1:54: broken: templates.grid: unknown template "grid", expected one of: ` + strings.Join(overridableTemplates, ", "): true,
+		`broken.json:1:79: broken: templates.record: template "missing" is not defined`:                                                   true,
+	}
+	for _, issue := range s.ValidateModel("broken") {
+		if !expected[issue.String()] {
+			t.Errorf("unexpected issue: %s", issue)
+		}
+		delete(expected, issue.String())
+	}
+	for issue := range expected {
+		t.Errorf("missing issue: %s", issue)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"maps"
+	"net/http"
+
+	"github.com/pa-pe/wedyta/model"
+)
+
+// httpError is returned from a transaction function to respond with the status and the message after the rollback
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func newHttpError(status int, message string) error {
+	return &httpError{status: status, message: message}
+}
+
+// respondTransactionError writes the error of a rolled back transaction to the response
+func respondTransactionError(ctx *model.Request, err error) {
+	var hErr *httpError
+	if errors.As(err, &hErr) {
+		ctx.JSON(hErr.status, model.H{"error": hErr.message})
+		return
+	}
+
+	log.Printf("Wedyta: transaction error: %v", err)
+	ctx.JSON(http.StatusInternalServerError, model.H{"error": "Internal Server Error"})
+}
+
+// transactionErrorMessage returns the message of the error for the client, the errors other than httpError are logged
+func transactionErrorMessage(err error) string {
+	var hErr *httpError
+	if errors.As(err, &hErr) {
+		return hErr.message
+	}
+
+	log.Printf("Wedyta: transaction error: %v", err)
+	return "Internal Server Error"
+}
+
+// takeRequestSnapshot copies the request data which may be used after the response is written
+func takeRequestSnapshot(ctx *model.Request) model.RequestSnapshot {
+	return model.RequestSnapshot{
+		Method:   ctx.Request.Method,
+		URL:      ctx.Request.URL.String(),
+		Header:   ctx.Request.Header.Clone(),
+		ClientIP: ctx.ClientIP(),
+		Keys:     maps.Clone(ctx.Keys),
+	}
+}
+
+// fireAfterCommit calls WedytaConfig.AfterCommit in a goroutine
+func (s *Service) fireAfterCommit(ctx *model.Request, mConfig *model.ConfigOfModel, action string, recordID int64, changes []model.FieldChange) {
+	if s.Config.AfterCommit == nil {
+		return
+	}
+
+	event := model.CommitEvent{
+		ModelName: mConfig.ModelName,
+		Table:     mConfig.DbTable,
+		Action:    action,
+		RecordID:  recordID,
+		Changes:   changes,
+		Request:   takeRequestSnapshot(ctx),
+	}
+
+	go s.Config.AfterCommit(event)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/gorm"
+)
+
+func TestHooksRollBack(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"fields": ["id", "name"], "editableFields": ["name"], "addableFields": ["name"], "deletable": true}`)},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+	if err := s.DB.Exec(`INSERT INTO items (name) VALUES ('apple')`).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	s.Config.AfterCreate = func(ctx *model.Request, db *gorm.DB, table string, id int64) error {
+		return errors.New("create is rejected")
+	}
+	s.Config.AfterUpdate = func(ctx *model.Request, db *gorm.DB, table string, id int64, field, valueBeforeUpdate, valueAfterUpdate string) error {
+		return errors.New("update is rejected")
+	}
+	s.Config.BeforeDelete = func(ctx *model.Request, db *gorm.DB, table string, id int64) error {
+		return errors.New("delete is rejected")
+	}
+
+	request := func(handler func(*model.Request), payload string) *model.Request {
+		ctx := model.NewRequest(httptest.NewRequest("POST", "/", strings.NewReader(payload)), nil)
+		handler(ctx)
+		return ctx
+	}
+	names := func() string {
+		var values []string
+		if err := s.DB.Table("items").Order("id").Pluck("name", &values).Error; err != nil {
+			t.Fatalf("failed to select names: %v", err)
+		}
+		return strings.Join(values, ",")
+	}
+
+	for _, tt := range []struct {
+		handler func(*model.Request)
+		payload string
+		message string
+	}{
+		{handler: s.HandleTableCreateRecord, payload: `{"modelName": "items", "name": "pear"}`, message: "create is rejected"},
+		{handler: s.Update, payload: `{"modelName": "items", "id": 1, "name": "plum"}`, message: "update is rejected"},
+		{handler: s.Delete, payload: `{"modelName": "items", "id": 1}`, message: "delete is rejected"},
+	} {
+		ctx := request(tt.handler, tt.payload)
+		if ctx.Response.Status != http.StatusBadRequest || !strings.Contains(string(ctx.Response.Body), tt.message) {
+			t.Errorf("expected %q, got %d %s", tt.message, ctx.Response.Status, ctx.Response.Body)
+		}
+		if names() != "apple" {
+			t.Errorf("the change of %s isn't rolled back: %s", tt.payload, names())
+		}
+	}
+}
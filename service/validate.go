@@ -0,0 +1,547 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pa-pe/wedyta/model"
+	"github.com/pa-pe/wedyta/utils"
+	"github.com/pa-pe/wedyta/utils/jsonschema"
+	"github.com/pa-pe/wedyta/utils/sqlutils"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/text/language"
+)
+
+var (
+	knownDisplayModes     = []string{"*", "all", "table", "record", "update", "create", "insert"}
+	knownPermissionAction = []string{"*", "read", "create", "update", "delete", "restore", "purge"}
+	linkPlaceholderRe     = regexp.MustCompile(`\$(\w+)\$`)
+)
+
+// Validate checks every model config of ConfigFS and every registered model against the database
+func (s *Service) Validate() model.ConfigIssues {
+	modelNames, err := s.ModelNames()
+	if err != nil {
+		return model.ConfigIssues{{Message: fmt.Sprintf("can't list model configs: %v", err)}}
+	}
+
+	var issues model.ConfigIssues
+	for _, modelName := range modelNames {
+		issues = append(issues, s.ValidateModel(modelName)...)
+	}
+
+	// fragments are checked as parts of the models extending them, here only against the schema
+	fragments, _ := fs.Glob(s.Config.ConfigFS, fragmentPrefix+"*")
+	for _, fragment := range fragments {
+		if !slices.Contains(configFileExtensions, path.Ext(fragment)) {
+			continue
+		}
+		v := &configValidator{s: s, file: fragment}
+		v.document = v.validateDocument()
+		issues = append(issues, v.locateIssues()...)
+	}
+	return issues
+}
+
+// ModelNames returns the sorted names of the models having a config file or registered by RegisterModel
+func (s *Service) ModelNames() ([]string, error) {
+	entries, err := fs.ReadDir(s.Config.ConfigFS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var modelNames []string
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || !slices.Contains(configFileExtensions, ext) || isConfigFragment(entry.Name()) {
+			continue
+		}
+		modelNames = append(modelNames, strings.TrimSuffix(entry.Name(), ext))
+	}
+
+	registryMu.RLock()
+	for modelName := range registeredModels {
+		modelNames = append(modelNames, modelName)
+	}
+	registryMu.RUnlock()
+
+	slices.Sort(modelNames)
+	return slices.Compact(modelNames), nil
+}
+
+// configValidator collects the issues of one model config
+type configValidator struct {
+	s         *Service
+	file      string
+	document  *jsonschema.Node
+	mConfig   *model.ConfigOfModel
+	columns   map[string]string
+	issues    model.ConfigIssues
+	modelName string
+}
+
+func (v *configValidator) addIssue(path, format string, args ...interface{}) {
+	v.issues = append(v.issues, model.ConfigIssue{ModelName: v.modelName, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateModel checks the model config: JSON Schema conformance, field names against the table columns,
+// relatedData tables and columns, parent existence and cycles, link templates and permissions
+func (s *Service) ValidateModel(modelName string) model.ConfigIssues {
+	v := &configValidator{s: s, modelName: modelName}
+
+	if configName, _, err := s.findConfigFile(modelName); err == nil {
+		v.file = configName
+		v.document = v.validateDocument()
+	}
+
+	mConfig, err := s.readRawModelConfig(modelName)
+	var presetsErr unknownFieldPresetsError
+	if errors.As(err, &presetsErr) {
+		for _, field := range presetsErr {
+			v.addIssue("fieldPresets."+field, "unknown field definition")
+		}
+		return v.locateIssues()
+	}
+	if err != nil {
+		if v.document != nil {
+			v.addIssue("", "%v", err)
+		}
+		return v.locateIssues()
+	}
+	v.mConfig = mConfig
+
+	if mConfig.DbTable == "" {
+		mConfig.DbTable = utils.CamelToSnake(modelName)
+	}
+
+	if columns, _ := sqlutils.GetTableColumnTypes(s.DB, mConfig.DbTable); len(columns) > 0 {
+		v.columns = columns
+	}
+	if v.columns == nil {
+		v.addIssue("dbTable", "table %q not found", mConfig.DbTable)
+	} else {
+		v.validateFields()
+	}
+
+	v.validateRelatedData()
+	v.validateCountRelatedData()
+	v.validateLinks()
+	v.validateParent()
+	v.validateChildren()
+	v.validateBulkActions()
+	v.validateRecordActions()
+	v.validatePermissions()
+	v.validateTemplates()
+	v.validateLocales()
+
+	// checked by the JSON Schema for config files
+	for _, field := range mapKeys(mConfig.FieldEditor) {
+		if v.document != nil {
+			break
+		}
+		if typ, ok := mConfig.FieldEditor[field]["type"]; !ok {
+			v.addIssue("fieldsEditor."+field, "no type")
+		} else if _, ok := typ.(string); !ok {
+			v.addIssue("fieldsEditor."+field+".type", "must be string")
+		}
+	}
+
+	if s.Config.VariableResolver == nil && strings.Contains(mConfig.SqlWhereOriginal, "{{") {
+		v.addIssue("sqlWhere", "variables are used without WedytaConfig.VariableResolver")
+	}
+
+	return v.locateIssues()
+}
+
+// validateDocument checks the config file against the JSON Schema of the model configs, reporting unknown keys and wrong types
+func (v *configValidator) validateDocument() *jsonschema.Node {
+	data, err := fs.ReadFile(v.s.Config.ConfigFS, v.file)
+	if err != nil {
+		v.addIssue("", "%v", err)
+		return nil
+	}
+
+	var document *jsonschema.Node
+	switch path.Ext(v.file) {
+	case ".yaml", ".yml":
+		document, err = jsonschema.ParseYAML(data)
+	case ".toml":
+		var raw map[string]interface{}
+		if err = toml.Unmarshal(data, &raw); err == nil {
+			document = jsonschema.FromValue(raw)
+		}
+	default:
+		document, err = jsonschema.ParseJSON(data)
+	}
+	if err != nil {
+		v.addIssue("", "%v", err)
+		return nil
+	}
+
+	for _, schemaErr := range jsonschema.Validate(model.ConfigJSONSchema(), document) {
+		v.issues = append(v.issues, model.ConfigIssue{
+			ModelName: v.modelName,
+			Path:      schemaErr.Path,
+			Message:   schemaErr.Message,
+			Line:      schemaErr.Line,
+			Column:    schemaErr.Column,
+		})
+	}
+
+	return document
+}
+
+// locateIssues sets the file and the position of the issues by their paths
+func (v *configValidator) locateIssues() model.ConfigIssues {
+	for i := range v.issues {
+		v.issues[i].File = v.file
+		if v.document != nil && v.issues[i].Line == 0 && v.issues[i].Path != "" {
+			pos := v.document.Lookup(v.issues[i].Path)
+			v.issues[i].Line, v.issues[i].Column = pos.Line, pos.Column
+		}
+	}
+	return v.issues
+}
+
+func (v *configValidator) isVirtualField(field string) bool {
+	_, columnDataFunc := v.mConfig.ColumnDataFunc[field]
+	_, countRelatedData := v.mConfig.CountRelatedData[field]
+	return columnDataFunc || countRelatedData
+}
+
+func (v *configValidator) checkColumn(path, field string) {
+	if _, exists := v.columns[field]; !exists {
+		v.addIssue(path, "unknown column %q of table %s", field, v.mConfig.DbTable)
+	}
+}
+
+func (v *configValidator) checkListedInFields(path, field string) {
+	if !slices.Contains(v.mConfig.Fields, field) && !slices.Contains(v.mConfig.Fields, utils.InvertCaseStyle(field)) {
+		v.addIssue(path, "field %q is not listed in fields", field)
+	}
+}
+
+func (v *configValidator) validateFields() {
+	mConfig := v.mConfig
+
+	if len(mConfig.Fields) == 0 {
+		v.addIssue("fields", "no fields")
+	}
+	for i, field := range mConfig.Fields {
+		if !v.isVirtualField(field) {
+			v.checkColumn(fmt.Sprintf("fields[%d]", i), field)
+		}
+	}
+
+	for _, list := range []struct {
+		key    string
+		fields []string
+	}{
+		{"editableFields", mConfig.EditableFields},
+		{"addableFields", mConfig.AddableFields},
+		{"requiredFields", mConfig.RequiredFields},
+		{"noZeroValueFields", mConfig.NoZeroValueFields},
+	} {
+		for i, field := range list.fields {
+			v.checkColumn(fmt.Sprintf("%s[%d]", list.key, i), field)
+		}
+	}
+
+	for _, keyed := range []struct {
+		key    string
+		fields []string
+	}{
+		{"headers", mapKeys(mConfig.Headers)},
+		{"titles", mapKeys(mConfig.Titles)},
+		{"classes", mapKeys(mConfig.Classes)},
+		{"displayMode", mapKeys(mConfig.DisplayMode)},
+		{"dateTimeFields", mapKeys(mConfig.DateTimeFields)},
+		{"fieldsEditor", mapKeys(mConfig.FieldEditor)},
+		{"password", mapKeys(mConfig.Password)},
+		{"columnDataFunc", mapKeys(mConfig.ColumnDataFunc)},
+		{"links", mapKeys(mConfig.Links)},
+		{"duplicate.resetFields", mConfig.Duplicate.ResetFields},
+	} {
+		for _, field := range keyed.fields {
+			v.checkListedInFields(keyed.key+"."+field, field)
+		}
+	}
+
+	for _, field := range mapKeys(mConfig.ColumnDataFunc) {
+		name := mConfig.ColumnDataFunc[field].Name
+		if _, found := v.s.lookupColumnFunc(name); !found && !slices.Contains(builtinColumnDataFuncs, name) {
+			v.addIssue("columnDataFunc."+field, "unknown columnDataFunc %q, expected one of: %s or a name of WedytaConfig.ColumnFuncs or Service.RegisterColumnFunc", name, strings.Join(builtinColumnDataFuncs, ", "))
+		}
+	}
+
+	for _, field := range mapKeys(mConfig.DisplayMode) {
+		for _, token := range strings.FieldsFunc(mConfig.DisplayMode[field], func(r rune) bool { return r == ',' || r == ' ' || r == '|' }) {
+			if !slices.Contains(knownDisplayModes, token) {
+				v.addIssue("displayMode."+field, "unknown display mode %q", token)
+			}
+		}
+	}
+
+	if mConfig.VersionField != "" {
+		v.checkColumn("versionField", mConfig.VersionField)
+	}
+	if mConfig.SoftDelete.Enabled() {
+		v.checkColumn("softDelete", mConfig.SoftDelete.Field)
+	}
+}
+
+func (v *configValidator) validateRelatedData() {
+	for _, field := range mapKeys(v.mConfig.RelatedData) {
+		related := v.mConfig.RelatedData[field]
+		path := "relatedData." + field
+		if v.columns != nil {
+			v.checkColumn(path, field)
+		}
+
+		if related.RawSql != "" {
+			parsed, ok := sqlutils.ParseRawSql(related.RawSql)
+			if !ok || len(parsed.Fields) != 2 || parsed.Table == "" {
+				v.addIssue(path, "RawSql must be SELECT key_field, value_field FROM table: %s", related.RawSql)
+				continue
+			}
+			prefixesToClean := []string{"DISTINCT "}
+			related.Table = parsed.Table
+			related.KeyField = utils.CleanPrefixes(parsed.Fields[0], prefixesToClean)
+			related.ValueField = utils.CleanPrefixes(parsed.Fields[1], prefixesToClean)
+		}
+
+		if related.Table == "" || related.ValueField == "" {
+			v.addIssue(path, "incomplete relatedData, table and valueField are required")
+			continue
+		}
+
+		relatedColumns, _ := sqlutils.GetTableColumnTypes(v.s.DB, related.Table)
+		if len(relatedColumns) == 0 {
+			v.addIssue(path, "related table %q not found", related.Table)
+			continue
+		}
+		for _, column := range []string{related.KeyField, related.ValueField} {
+			if _, exists := relatedColumns[column]; column != "" && !exists {
+				v.addIssue(path, "unknown column %q of related table %s", column, related.Table)
+			}
+		}
+	}
+}
+
+func (v *configValidator) validateCountRelatedData() {
+	for _, field := range mapKeys(v.mConfig.CountRelatedData) {
+		countConfig := v.mConfig.CountRelatedData[field]
+		path := "countRelatedData." + field
+		if v.columns != nil {
+			v.checkColumn(path+".localFieldID", countConfig.LocalFieldID)
+		}
+
+		targetColumns, _ := sqlutils.GetTableColumnTypes(v.s.DB, countConfig.Table)
+		if len(targetColumns) == 0 {
+			v.addIssue(path+".table", "table %q not found", countConfig.Table)
+			continue
+		}
+		if _, exists := targetColumns[countConfig.TargetFieldID]; !exists {
+			v.addIssue(path+".targetFieldID", "unknown column %q of table %s", countConfig.TargetFieldID, countConfig.Table)
+		}
+	}
+}
+
+func (v *configValidator) validateLinks() {
+	for _, field := range mapKeys(v.mConfig.Links) {
+		link := v.mConfig.Links[field]
+		path := "links." + field
+		switch {
+		case link.Preset != "" && link.Preset != "self":
+			v.addIssue(path+".preset", "unknown preset %q", link.Preset)
+		case link.Preset == "" && link.Template == "":
+			v.addIssue(path, "preset or template is required")
+		}
+
+		if v.columns == nil {
+			continue
+		}
+		for _, match := range linkPlaceholderRe.FindAllStringSubmatch(link.Template, -1) {
+			v.checkColumn(path+".template", match[1])
+		}
+	}
+}
+
+func (v *configValidator) validateParent() {
+	parent := v.mConfig.Parent
+	if parent.ModelName == "" {
+		return
+	}
+
+	if !v.s.modelExists(parent.ModelName) {
+		v.addIssue("parent.modelName", "model %q not found", parent.ModelName)
+		return
+	}
+
+	if parent.LocalConnectionField != "" && v.columns != nil {
+		v.checkColumn("parent.localConnectionField", parent.LocalConnectionField)
+	}
+
+	// walk up the chain of parents
+	visited := []string{v.modelName}
+	for modelName := parent.ModelName; modelName != ""; {
+		if slices.Contains(visited, modelName) {
+			v.addIssue("parent.modelName", "parent cycle: %s -> %s", strings.Join(visited, " -> "), modelName)
+			return
+		}
+		visited = append(visited, modelName)
+
+		mConfig, err := v.s.readRawModelConfig(modelName)
+		if err != nil {
+			// reported by the validation of that model
+			return
+		}
+		modelName = mConfig.Parent.ModelName
+	}
+}
+
+func (v *configValidator) validateChildren() {
+	for i, child := range v.mConfig.Children {
+		path := fmt.Sprintf("children.%d", i)
+		childConfig, err := v.s.readRawModelConfig(child)
+		if err != nil {
+			v.addIssue(path, "model %q not found", child)
+			continue
+		}
+		if childConfig.Parent.ModelName != v.modelName {
+			v.addIssue(path, "parent of model %q is %q", child, childConfig.Parent.ModelName)
+		} else if childConfig.Parent.QueryVariableName == "" {
+			v.addIssue(path, "model %q has no parent.queryVariableName", child)
+		}
+	}
+}
+
+func (v *configValidator) validateBulkActions() {
+	for i, action := range v.mConfig.BulkActions {
+		path := fmt.Sprintf("bulkActions.%d", i)
+		switch action {
+		case bulkActionDelete:
+			if !v.mConfig.Deletable {
+				v.addIssue(path, "model is not deletable")
+			}
+		case bulkActionEnable, bulkActionDisable:
+			if !slices.Contains(v.mConfig.EditableFields, bulkSwitchField) {
+				v.addIssue(path, "%s is not listed in editableFields", bulkSwitchField)
+			}
+		case bulkActionSetField:
+			if len(v.mConfig.EditableFields) == 0 {
+				v.addIssue(path, "model has no editableFields")
+			}
+		default:
+			if _, exists := v.s.Config.BulkActions[action]; !exists {
+				v.addIssue(path, "unknown bulk action %q, expected one of: %s or a name of WedytaConfig.BulkActions", action, strings.Join(builtinBulkActions, ", "))
+			}
+		}
+	}
+}
+
+// validateRecordActions checks that the actions are registered and have a place
+func (v *configValidator) validateRecordActions() {
+	if len(v.mConfig.RecordActions) == 0 {
+		return
+	}
+	for i, name := range v.mConfig.RecordActions {
+		if _, found := v.s.lookupRecordAction(name); !found {
+			v.addIssue(fmt.Sprintf("recordActions.%d", i), "unknown record action %q, expected a name of WedytaConfig.RecordActions or Service.RegisterRecordAction", name)
+		}
+	}
+	for _, columnDataFunc := range v.mConfig.ColumnDataFunc {
+		if columnDataFunc.Name == "stdRecordControls" {
+			return
+		}
+	}
+	v.addIssue("recordActions", "no field has columnDataFunc stdRecordControls showing the actions")
+}
+
+func (v *configValidator) validateTemplates() {
+	v.s.cacheMu.RLock()
+	templates := v.s.templates
+	v.s.cacheMu.RUnlock()
+
+	for _, name := range mapKeys(v.mConfig.Templates) {
+		if !slices.Contains(overridableTemplates, name) {
+			v.addIssue("templates."+name, "unknown template %q, expected one of: %s", name, strings.Join(overridableTemplates, ", "))
+		} else if templates.Lookup(v.mConfig.Templates[name]) == nil {
+			v.addIssue("templates."+name, "template %q is not defined", v.mConfig.Templates[name])
+		}
+	}
+}
+
+func (v *configValidator) validateLocales() {
+	for _, locale := range mapKeys(v.mConfig.Locales) {
+		texts := v.mConfig.Locales[locale]
+		path := "locales." + locale
+		if _, err := language.Parse(locale); err != nil {
+			v.addIssue(path, "invalid locale %q: %v", locale, err)
+		}
+		for _, field := range mapKeys(texts.Headers) {
+			v.checkListedInFields(path+".headers."+field, field)
+		}
+		for _, field := range mapKeys(texts.Titles) {
+			v.checkListedInFields(path+".titles."+field, field)
+		}
+	}
+}
+
+// permissionActions returns the actions of the "permissions" matrix: the built-in ones,
+// the custom bulk actions and the record actions with their permissions
+func (v *configValidator) permissionActions() []string {
+	actions := slices.Clone(knownPermissionAction)
+	for _, action := range v.mConfig.BulkActions {
+		if !slices.Contains(builtinBulkActions, action) {
+			actions = append(actions, action)
+		}
+	}
+	for _, name := range v.mConfig.RecordActions {
+		if recordAction, found := v.s.lookupRecordAction(name); found {
+			actions = append(actions, recordAction.Permission)
+		}
+	}
+	return actions
+}
+
+func (v *configValidator) validatePermissions() {
+	knownActions := v.permissionActions()
+	for _, role := range mapKeys(v.mConfig.Permissions) {
+		permission := v.mConfig.Permissions[role]
+		path := "permissions." + role
+		for _, action := range permission.Actions {
+			if !slices.Contains(knownActions, action) {
+				v.addIssue(path+".actions", "unknown action %q", action)
+			}
+		}
+		for _, action := range mapKeys(permission.Fields) {
+			fields := permission.Fields[action]
+			if !slices.Contains(knownActions, action) {
+				v.addIssue(path+".fields."+action, "unknown action %q", action)
+			}
+			if v.columns == nil {
+				continue
+			}
+			for _, field := range fields {
+				if !v.isVirtualField(field) {
+					v.checkColumn(path+".fields."+action, field)
+				}
+			}
+		}
+	}
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pa-pe/wedyta/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestValidate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "name", "controls"], "columnDataFunc": {"controls": "stdRecordControls"},
+			"editableFields": ["name"], "links": {"id": {"preset": "self"}}}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "items", "fields": ["id", "nmae"], "editableFields": ["colour"],
+			"relatedData": {"owner_id": "missing.name"}, "links": {"id": {"template": "/items/$idd$"}},
+			"fieldsEditor": {"name": {"rows": 3}}, "parent": {"modelName": "child"}, "unknownKey": true}`)},
+		"child.yaml":  {Data: []byte("dbTable: items\nfields: [id]\nparent:\n  modelName: broken\n")},
+		"orphan.json": {Data: []byte(`{"dbTable": "missing_table", "fields": ["id"], "parent": {"modelName": "nobody"}}`)},
+		"readme.txt":  {Data: []byte("not a config")},
+	}
+	s, _ := newConfigFileTestService(t, fsys)
+
+	if issues := s.ValidateModel("items"); len(issues) != 0 {
+		t.Errorf("expected no issues for valid config, got:\n%v", issues.Error())
+	}
+
+	expected := map[string]bool{
+		`broken.json:3:77: broken: unknownKey: unknown key "unknownKey"`:                      true,
+		`broken.json:3:21: broken: fieldsEditor.name: missing required key "type"`:            true,
+		`broken.json:1:39: broken: fields[1]: unknown column "nmae" of table items`:           true,
+		`broken.json:1:67: broken: editableFields[0]: unknown column "colour" of table items`: true,
+		`broken.json:2:20: broken: relatedData.owner_id: related table "missing" not found`:   true,
+		`broken.json:2:66: broken: links.id.template: unknown column "idd" of table items`:    true,
+		`broken.json:3:21: broken: fieldsEditor.name: field "name" is not listed in fields`:   true,
+		`broken.json:3:54: broken: parent.modelName: parent cycle: broken -> child -> broken`: true,
+		`child.yaml:4:3: child: parent.modelName: parent cycle: child -> broken -> child`:     true,
+		`orphan.json:1:2: orphan: dbTable: table "missing_table" not found`:                   true,
+		`orphan.json:1:59: orphan: parent.modelName: model "nobody" not found`:                true,
+	}
+
+	issues := s.Validate()
+	for _, issue := range issues {
+		if !expected[issue.String()] {
+			t.Errorf("unexpected issue: %s", issue)
+		}
+		delete(expected, issue.String())
+	}
+	for issue := range expected {
+		t.Errorf("missing issue: %s", issue)
+	}
+}
+
+func TestConfigValidationStrict(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite test database: %v", err)
+	}
+	cfg := &model.WedytaConfig{ConfigValidation: model.ConfigValidationStrict, AssetsSource: model.AssetsSourceCDN, ConfigFS: fstest.MapFS{
+		"broken.json": {Data: []byte(`{"dbTable": "missing_table", "fields": ["id"]}`)},
+	}}
+
+	if _, err := NewServiceE(db, cfg); err == nil || !strings.Contains(err.Error(), `table "missing_table" not found`) {
+		t.Errorf("expected the error of the invalid config, got %v", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Errorf("expected NewService to panic")
+		}
+	}()
+	NewService(db, cfg)
+}
+
+func TestConfigValidationUnavailable(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.json":  {Data: []byte(`{"fields": ["id", "name"]}`)},
+		"broken.json": {Data: []byte(`{"dbTable": "items", "fields": ["id"], "fieldsEditor": {"id": {"rows": 3}}}`)},
+	}
+	s, ctx := newConfigFileTestService(t, fsys)
+	s.unavailableModels = s.Validate().ByModel()
+
+	if _, err := s.prepareModelConfig(ctx, "items", nil); err != nil {
+		t.Errorf("valid model must be available, got: %v", err)
+	}
+	if _, err := s.prepareModelConfig(ctx, "broken", nil); err == nil {
+		t.Errorf("expected broken model to be unavailable")
+	}
+}
+
+func TestFillFieldConfig_EditorWithoutType(t *testing.T) {
+	s, _ := newConfigFileTestService(t, fstest.MapFS{})
+
+	mConfig := &model.ConfigOfModel{DbTable: "items", Fields: []string{"name"}, FieldEditor: map[string]model.FieldEditorConfig{"name": {"rows": 3}}}
+	if err := s.fillFieldConfig(mConfig); err == nil {
+		t.Errorf("expected error for fieldsEditor without type")
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configWatchDelay collects the events of a save by an editor or a deploy into one reload
+const configWatchDelay = 200 * time.Millisecond
+
+type configWatcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// configModelName returns the model name of the config file, empty for fragments and other files
+func configModelName(name string) string {
+	ext := path.Ext(name)
+	if !slices.Contains(configFileExtensions, ext) || isConfigFragment(name) {
+		return ""
+	}
+	return strings.TrimSuffix(name, ext)
+}
+
+// watchConfigDir starts reloading the configs changed in ConfigDir in the background
+func (s *Service) watchConfigDir() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// subfolders are not watched by fsnotify
+	err = filepath.WalkDir(s.Config.ConfigDir, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		return watcher.Add(dir)
+	})
+	if err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	s.watcher = &configWatcher{watcher: watcher, done: make(chan struct{})}
+	go s.watchConfigEvents(s.watcher)
+	return nil
+}
+
+func (s *Service) watchConfigEvents(w *configWatcher) {
+	defer close(w.done)
+
+	changed := make(map[string]struct{})
+	var delay <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.watcher.Add(event.Name); err != nil {
+						log.Printf("WeDyTa: config watcher: %v", err)
+					}
+					continue
+				}
+			}
+			if name, err := filepath.Rel(s.Config.ConfigDir, event.Name); err == nil {
+				changed[filepath.ToSlash(name)] = struct{}{}
+				delay = time.After(configWatchDelay)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("WeDyTa: config watcher: %v", err)
+		case <-delay:
+			names := make([]string, 0, len(changed))
+			for name := range changed {
+				names = append(names, name)
+			}
+			clear(changed)
+			delay = nil
+
+			if err := s.reloadConfigFiles(names); err != nil {
+				log.Printf("WeDyTa: reload of changed model configs: %v", err)
+			}
+		}
+	}
+}
+
+// Close stops the watcher of WedytaConfig.WatchConfigDir, the configs are not reloaded after that
+func (s *Service) Close() error {
+	if s.watcher == nil {
+		return nil
+	}
+	err := s.watcher.watcher.Close()
+	<-s.watcher.done
+	return err
+}
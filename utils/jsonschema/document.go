@@ -0,0 +1,299 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Kind int
+
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+func (k Kind) String() string {
+	return [...]string{"null", "boolean", "number", "string", "array", "object"}[k]
+}
+
+// Position in the source document, zero Line if unknown
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Node is a value of a config document with its position in the source
+type Node struct {
+	Kind  Kind
+	Value interface{} // bool, float64 or string of the scalars
+	Position
+
+	Keys         []string // object keys in the document order
+	Fields       map[string]*Node
+	KeyPositions map[string]Position
+	Items        []*Node
+}
+
+func newObjectNode(pos Position) *Node {
+	return &Node{Kind: Object, Position: pos, Fields: make(map[string]*Node), KeyPositions: make(map[string]Position)}
+}
+
+func (n *Node) setField(key string, keyPos Position, value *Node) {
+	if _, exists := n.Fields[key]; !exists {
+		n.Keys = append(n.Keys, key)
+	}
+	n.Fields[key] = value
+	n.KeyPositions[key] = keyPos
+}
+
+// Lookup returns the position of the value addressed by the path like "relatedData.user_id" or "fields[2]",
+// object members are pointed by their keys. The position of the deepest existing node is returned for missing paths.
+func (n *Node) Lookup(path string) Position {
+	pos := n.Position
+	node := n
+	for _, segment := range splitPath(path) {
+		if node == nil {
+			break
+		}
+		if index, isIndex := segment.(int); isIndex {
+			if node.Kind != Array || index >= len(node.Items) {
+				break
+			}
+			node = node.Items[index]
+			pos = node.Position
+			continue
+		}
+
+		key := segment.(string)
+		if node.Kind != Object || node.Fields[key] == nil {
+			break
+		}
+		pos = node.KeyPositions[key]
+		node = node.Fields[key]
+	}
+	return pos
+}
+
+// splitPath splits "a.b[1].c" into "a", "b", 1, "c"
+func splitPath(path string) []interface{} {
+	var segments []interface{}
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		for {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				segments = append(segments, part)
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			end := strings.IndexByte(part[open:], ']')
+			if end < 0 {
+				break
+			}
+			if index, err := strconv.Atoi(part[open+1 : open+end]); err == nil {
+				segments = append(segments, index)
+			}
+			part = part[open+end+1:]
+			if part == "" {
+				break
+			}
+		}
+	}
+	return segments
+}
+
+// ParseJSON parses the JSON document keeping the positions of the values
+func ParseJSON(data []byte) (*Node, error) {
+	p := &jsonParser{data: data, decoder: json.NewDecoder(bytes.NewReader(data))}
+	p.decoder.UseNumber()
+	return p.parseValue()
+}
+
+type jsonParser struct {
+	data    []byte
+	decoder *json.Decoder
+}
+
+// nextPosition returns the position of the next token, skipping whitespace and separators
+func (p *jsonParser) nextPosition() Position {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return offsetToPosition(p.data, offset)
+}
+
+func offsetToPosition(data []byte, offset int) Position {
+	before := data[:min(offset, len(data))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+	return Position{Line: line, Column: column}
+}
+
+func (p *jsonParser) parseValue() (*Node, error) {
+	pos := p.nextPosition()
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("line %d:%d: %v", pos.Line, pos.Column, err)
+	}
+	return p.parseToken(token, pos)
+}
+
+func (p *jsonParser) parseToken(token json.Token, pos Position) (*Node, error) {
+	switch v := token.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			node := newObjectNode(pos)
+			for p.decoder.More() {
+				keyPos := p.nextPosition()
+				keyToken, err := p.decoder.Token()
+				if err != nil {
+					return nil, fmt.Errorf("line %d:%d: %v", keyPos.Line, keyPos.Column, err)
+				}
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				node.setField(keyToken.(string), keyPos, value)
+			}
+			_, err := p.decoder.Token() // }
+			return node, err
+		case '[':
+			node := &Node{Kind: Array, Position: pos}
+			for p.decoder.More() {
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				node.Items = append(node.Items, item)
+			}
+			_, err := p.decoder.Token() // ]
+			return node, err
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: Number, Value: f, Position: pos}, nil
+	case string:
+		return &Node{Kind: String, Value: v, Position: pos}, nil
+	case bool:
+		return &Node{Kind: Bool, Value: v, Position: pos}, nil
+	case nil:
+		return &Node{Kind: Null, Position: pos}, nil
+	}
+	return nil, fmt.Errorf("line %d:%d: unexpected token %v", pos.Line, pos.Column, token)
+}
+
+// ParseYAML parses the YAML document keeping the positions of the values
+func ParseYAML(data []byte) (*Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return newObjectNode(Position{Line: 1, Column: 1}), nil
+	}
+	return fromYAMLNode(document.Content[0])
+}
+
+func fromYAMLNode(yn *yaml.Node) (*Node, error) {
+	pos := Position{Line: yn.Line, Column: yn.Column}
+
+	switch yn.Kind {
+	case yaml.AliasNode:
+		return fromYAMLNode(yn.Alias)
+	case yaml.MappingNode:
+		node := newObjectNode(pos)
+		for i := 0; i+1 < len(yn.Content); i += 2 {
+			keyNode, valueNode := yn.Content[i], yn.Content[i+1]
+			value, err := fromYAMLNode(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			node.setField(keyNode.Value, Position{Line: keyNode.Line, Column: keyNode.Column}, value)
+		}
+		return node, nil
+	case yaml.SequenceNode:
+		node := &Node{Kind: Array, Position: pos}
+		for _, itemNode := range yn.Content {
+			item, err := fromYAMLNode(itemNode)
+			if err != nil {
+				return nil, err
+			}
+			node.Items = append(node.Items, item)
+		}
+		return node, nil
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := yn.Decode(&value); err != nil {
+			return nil, fmt.Errorf("line %d:%d: %v", yn.Line, yn.Column, err)
+		}
+		node := FromValue(value)
+		node.Position = pos
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("line %d:%d: unsupported YAML node", yn.Line, yn.Column)
+}
+
+// FromValue converts the decoded value (maps, slices and scalars) to a Node without positions
+func FromValue(value interface{}) *Node {
+	switch v := value.(type) {
+	case nil:
+		return &Node{Kind: Null}
+	case bool:
+		return &Node{Kind: Bool, Value: v}
+	case string:
+		return &Node{Kind: String, Value: v}
+	case time.Time:
+		return &Node{Kind: String, Value: v.Format(time.RFC3339Nano)}
+	case map[string]interface{}:
+		node := newObjectNode(Position{})
+		for _, key := range sortedKeys(v) {
+			node.setField(key, Position{}, FromValue(v[key]))
+		}
+		return node
+	case []interface{}:
+		node := &Node{Kind: Array}
+		for _, item := range v {
+			node.Items = append(node.Items, FromValue(item))
+		}
+		return node
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Node{Kind: Number, Value: float64(rv.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Node{Kind: Number, Value: float64(rv.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return &Node{Kind: Number, Value: rv.Float()}
+	}
+
+	return &Node{Kind: String, Value: fmt.Sprint(value)}
+}
+
+func isInteger(value interface{}) bool {
+	f, ok := value.(float64)
+	return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+}
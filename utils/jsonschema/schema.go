@@ -0,0 +1,113 @@
+// Package jsonschema generates JSON Schema documents from Go types and validates config documents against them.
+//
+// Only the subset of JSON Schema needed by the wedyta configs is supported:
+// type, properties, additionalProperties, items, enum, anyOf and descriptions.
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is *Schema or false
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+
+	Items *Schema       `json:"items,omitempty"`
+	Enum  []interface{} `json:"enum,omitempty"`
+	AnyOf []*Schema     `json:"anyOf,omitempty"`
+}
+
+// Provider is implemented by types with a custom JSON form, e.g. a string-or-object UnmarshalJSON
+type Provider interface {
+	JSONSchema() *Schema
+}
+
+// Describer is implemented by structs to describe their properties, mapped by JSON name
+type Describer interface {
+	JSONSchemaDescriptions() map[string]string
+}
+
+var (
+	providerType  = reflect.TypeOf((*Provider)(nil)).Elem()
+	describerType = reflect.TypeOf((*Describer)(nil)).Elem()
+)
+
+// Generate returns the schema of the type.
+// Struct fields are taken by their json tags, untagged and "-" fields are skipped,
+// structs don't permit additional properties.
+func Generate(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Implements(providerType) {
+		return reflect.Zero(t).Interface().(Provider).JSONSchema()
+	}
+	if reflect.PointerTo(t).Implements(providerType) {
+		return reflect.New(t).Interface().(Provider).JSONSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: Generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: Generate(t.Elem())}
+	case reflect.Struct:
+		return generateStruct(t)
+	default:
+		// interface{} and the rest accept any value
+		return &Schema{}
+	}
+}
+
+func generateStruct(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+
+	var descriptions map[string]string
+	if t.Implements(describerType) {
+		descriptions = reflect.Zero(t).Interface().(Describer).JSONSchemaDescriptions()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := Generate(field.Type)
+		if description, exists := descriptions[name]; exists {
+			// copy, the schema of a Provider may be shared
+			described := *property
+			described.Description = description
+			property = &described
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
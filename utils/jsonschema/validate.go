@@ -0,0 +1,150 @@
+package jsonschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Error is a mismatch of the document and the schema
+type Error struct {
+	Path string // e.g. "relatedData.user_id" or "fields[2]", empty for the document root
+	Position
+	Message string
+}
+
+func (e Error) Error() string {
+	location := e.Path
+	if e.Line > 0 {
+		location = fmt.Sprintf("line %d:%d %s", e.Line, e.Column, e.Path)
+	}
+	if location == "" {
+		return e.Message
+	}
+	return strings.TrimSpace(location) + ": " + e.Message
+}
+
+// Validate checks the document against the schema, null values are accepted for every type like encoding/json does
+func Validate(schema *Schema, node *Node) []Error {
+	var errs []Error
+	validate(schema, node, "", node.Position, &errs)
+	return errs
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func validate(schema *Schema, node *Node, path string, pos Position, errs *[]Error) {
+	if schema == nil || node.Kind == Null {
+		return
+	}
+
+	if len(schema.AnyOf) > 0 {
+		validateAnyOf(schema, node, path, pos, errs)
+		return
+	}
+
+	if schema.Type != "" && !kindMatches(schema.Type, node) {
+		*errs = append(*errs, Error{Path: path, Position: pos, Message: fmt.Sprintf("expected %s, got %s", schema.Type, node.Kind)})
+		return
+	}
+
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, node.Value) {
+		*errs = append(*errs, Error{Path: path, Position: pos, Message: fmt.Sprintf("value %v is not one of %v", node.Value, schema.Enum)})
+	}
+
+	switch node.Kind {
+	case Object:
+		for _, key := range node.Keys {
+			keyPath := joinPath(path, key)
+			keyPos := node.KeyPositions[key]
+			if property := lookupProperty(schema.Properties, key); property != nil {
+				validate(property, node.Fields[key], keyPath, keyPos, errs)
+				continue
+			}
+
+			switch additional := schema.AdditionalProperties.(type) {
+			case *Schema:
+				validate(additional, node.Fields[key], keyPath, keyPos, errs)
+			case bool:
+				if !additional {
+					*errs = append(*errs, Error{Path: keyPath, Position: keyPos, Message: fmt.Sprintf("unknown key %q", key)})
+				}
+			}
+		}
+
+		for _, required := range schema.Required {
+			if _, exists := node.Fields[required]; !exists {
+				*errs = append(*errs, Error{Path: path, Position: pos, Message: fmt.Sprintf("missing required key %q", required)})
+			}
+		}
+	case Array:
+		for i, item := range node.Items {
+			validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), item.Position, errs)
+		}
+	}
+}
+
+// validateAnyOf reports the errors of the alternative of the node's type, or the type mismatch if there is no such alternative
+func validateAnyOf(schema *Schema, node *Node, path string, pos Position, errs *[]Error) {
+	var types []string
+	var firstErrs []Error
+	for _, alternative := range schema.AnyOf {
+		types = append(types, alternative.Type)
+		if alternative.Type != "" && !kindMatches(alternative.Type, node) {
+			continue
+		}
+
+		var alternativeErrs []Error
+		validate(alternative, node, path, pos, &alternativeErrs)
+		if len(alternativeErrs) == 0 {
+			return
+		}
+		if firstErrs == nil {
+			firstErrs = alternativeErrs
+		}
+	}
+
+	if firstErrs != nil {
+		*errs = append(*errs, firstErrs...)
+		return
+	}
+	*errs = append(*errs, Error{Path: path, Position: pos, Message: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), node.Kind)})
+}
+
+func kindMatches(schemaType string, node *Node) bool {
+	switch schemaType {
+	case "integer":
+		return node.Kind == Number && isInteger(node.Value)
+	case "number":
+		return node.Kind == Number
+	default:
+		return schemaType == node.Kind.String()
+	}
+}
+
+// lookupProperty finds the property by the key, case-insensitively like encoding/json matches the struct fields
+func lookupProperty(properties map[string]*Schema, key string) *Schema {
+	if property, exists := properties[key]; exists {
+		return property
+	}
+	for known, property := range properties {
+		if strings.EqualFold(known, key) {
+			return property
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testEntry struct {
+	Name string `json:"name"`
+}
+
+func (testEntry) JSONSchema() *Schema {
+	return &Schema{AnyOf: []*Schema{
+		{Type: "string"},
+		{Type: "object", Properties: map[string]*Schema{"name": {Type: "string"}}, AdditionalProperties: false},
+	}}
+}
+
+type testConfig struct {
+	Title   string               `json:"title"`
+	Count   int                  `json:"count"`
+	Tags    []string             `json:"tags"`
+	Entries map[string]testEntry `json:"entries"`
+	Runtime string               `json:"-"`
+	Hidden  string
+}
+
+func (testConfig) JSONSchemaDescriptions() map[string]string {
+	return map[string]string{"title": "Page title"}
+}
+
+func TestGenerate(t *testing.T) {
+	schema := Generate(reflect.TypeOf(testConfig{}))
+
+	if schema.Type != "object" || schema.AdditionalProperties != false {
+		t.Errorf("expected closed object, got %+v", schema)
+	}
+	if len(schema.Properties) != 4 {
+		t.Errorf("expected 4 properties, got %v", schema.Properties)
+	}
+	if schema.Properties["title"].Description != "Page title" || schema.Properties["count"].Type != "integer" {
+		t.Errorf("unexpected properties: %+v %+v", schema.Properties["title"], schema.Properties["count"])
+	}
+	if schema.Properties["tags"].Items.Type != "string" {
+		t.Errorf("unexpected tags schema: %+v", schema.Properties["tags"])
+	}
+	if entries := schema.Properties["entries"].AdditionalProperties.(*Schema); len(entries.AnyOf) != 2 {
+		t.Errorf("expected schema of the provider, got %+v", entries)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := Generate(reflect.TypeOf(testConfig{}))
+
+	tests := []struct {
+		name     string
+		parse    func([]byte) (*Node, error)
+		document string
+		expected []string
+	}{
+		{
+			name:     "valid json",
+			parse:    ParseJSON,
+			document: `{"title": "T", "Count": 2, "tags": ["a"], "entries": {"a": "x", "b": {"name": "y"}, "c": null}}`,
+		},
+		{
+			name:  "json",
+			parse: ParseJSON,
+			document: `{
+  "title": 1,
+  "count": 1.5,
+  "tags": ["a", 2],
+  "entries": {"a": true, "b": {"nmae": "y"}},
+  "unknown": {}
+}`,
+			expected: []string{
+				"line 2:3 title: expected string, got number",
+				"line 3:3 count: expected integer, got number",
+				"line 4:17 tags[1]: expected string, got number",
+				"line 5:15 entries.a: expected string or object, got boolean",
+				`line 5:32 entries.b.nmae: unknown key "nmae"`,
+				`line 6:3 unknown: unknown key "unknown"`,
+			},
+		},
+		{
+			name:     "yaml",
+			parse:    ParseYAML,
+			document: "title: T\ntags:\n  - a\n  - [b]\nextra: 1\n",
+			expected: []string{
+				"line 4:5 tags[1]: expected string, got array",
+				`line 5:1 extra: unknown key "extra"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		document, err := tt.parse([]byte(tt.document))
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.name, err)
+			continue
+		}
+
+		var got []string
+		for _, e := range Validate(schema, document) {
+			got = append(got, e.Error())
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	document, err := ParseJSON([]byte("{\n  \"fields\": [\"id\",\n    \"name\"],\n  \"links\": {\"id\": {\"template\": \"/x\"}}\n}"))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	tests := map[string]Position{
+		"fields[1]":         {Line: 3, Column: 5},
+		"links.id.template": {Line: 4, Column: 20},
+		"links.missing":     {Line: 4, Column: 3},
+		"":                  {Line: 1, Column: 1},
+	}
+	for path, expected := range tests {
+		if got := document.Lookup(path); got != expected {
+			t.Errorf("%q: expected %v, got %v", path, expected, got)
+		}
+	}
+}
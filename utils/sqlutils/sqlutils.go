@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,23 +18,51 @@ type ColumnSchema struct {
 	Type         string
 	IsPrimaryKey bool
 	IsNullable   bool
-	DefaultValue any
+	DefaultValue any // nil if the column has no default value
+	// TimePrecision is the number of the fractional second digits stored by the date and time columns
+	TimePrecision int
 }
 
-var tableSchemaCache = make(map[string][]ColumnSchema)
+type ForeignKey struct {
+	Column          string
+	ReferencedTable string
+	// ReferencedColumn may be empty if the key references the primary key (sqlite)
+	ReferencedColumn string
+}
+
+var (
+	tableSchemaCache   = make(map[string][]ColumnSchema)
+	tableSchemaCacheMu sync.RWMutex
+)
+
+// InvalidateTableSchema drops the cached schema of the tables, all tables if none given, e.g. after a migration
+func InvalidateTableSchema(tableNames ...string) {
+	tableSchemaCacheMu.Lock()
+	defer tableSchemaCacheMu.Unlock()
+
+	if len(tableNames) == 0 {
+		tableSchemaCache = make(map[string][]ColumnSchema)
+		return
+	}
+	for _, tableName := range tableNames {
+		delete(tableSchemaCache, tableName)
+	}
+}
 
 func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
-	if schema, ok := tableSchemaCache[tableName]; ok {
+	tableSchemaCacheMu.RLock()
+	schema, ok := tableSchemaCache[tableName]
+	tableSchemaCacheMu.RUnlock()
+	if ok {
 		return schema, nil
 	}
 
-	var schema []ColumnSchema
 	dialector := db.Dialector.Name()
 
 	switch dialector {
 	case "mysql":
 		query := `
-			SELECT COLUMN_NAME, DATA_TYPE, COLUMN_KEY, IS_NULLABLE, COLUMN_DEFAULT
+			SELECT COLUMN_NAME, DATA_TYPE, COLUMN_KEY, IS_NULLABLE, COLUMN_DEFAULT, DATETIME_PRECISION
 			FROM INFORMATION_SCHEMA.COLUMNS
 			WHERE TABLE_SCHEMA = DATABASE()
 			  AND TABLE_NAME = ?
@@ -44,6 +73,7 @@ func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
 			ColumnKey     string         `gorm:"column:COLUMN_KEY"`
 			IsNullable    string         `gorm:"column:IS_NULLABLE"`
 			ColumnDefault sql.NullString `gorm:"column:COLUMN_DEFAULT"`
+			TimePrecision sql.NullInt64  `gorm:"column:DATETIME_PRECISION"`
 		}
 		var results []mysqlCol
 		if err := db.Raw(query, tableName).Scan(&results).Error; err != nil {
@@ -52,11 +82,12 @@ func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
 		//fmt.Printf("%+v\n", results) // dbg
 		for _, col := range results {
 			schema = append(schema, ColumnSchema{
-				Name:         col.ColumnName,
-				Type:         strings.ToLower(col.DataType),
-				IsPrimaryKey: col.ColumnKey == "PRI",
-				IsNullable:   col.IsNullable == "YES",
-				DefaultValue: safeString(col.ColumnDefault),
+				Name:          col.ColumnName,
+				Type:          strings.ToLower(col.DataType),
+				IsPrimaryKey:  col.ColumnKey == "PRI",
+				IsNullable:    col.IsNullable == "YES",
+				DefaultValue:  nullableString(col.ColumnDefault),
+				TimePrecision: int(col.TimePrecision.Int64),
 			})
 		}
 
@@ -88,11 +119,12 @@ func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
 		}
 		for _, col := range results {
 			schema = append(schema, ColumnSchema{
-				Name:         col.ColumnName,
-				Type:         strings.ToLower(col.DataType),
-				IsPrimaryKey: col.IsPrimaryKey,
-				IsNullable:   col.IsNullable,
-				DefaultValue: safeString(col.ColumnDefault),
+				Name:          col.ColumnName,
+				Type:          strings.ToLower(col.DataType),
+				IsPrimaryKey:  col.IsPrimaryKey,
+				IsNullable:    col.IsNullable,
+				DefaultValue:  nullableString(col.ColumnDefault),
+				TimePrecision: postgresTimePrecision(col.DataType),
 			})
 		}
 
@@ -116,7 +148,9 @@ func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
 				Type:         strings.ToLower(col.Type),
 				IsPrimaryKey: col.PK > 0,
 				IsNullable:   col.NotNull == 0,
-				DefaultValue: col.DefaultValue,
+				DefaultValue: nullableString(col.DefaultValue),
+				// the driver stores the time as text with nanoseconds
+				TimePrecision: 9,
 			})
 		}
 
@@ -124,10 +158,124 @@ func getTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", dialector)
 	}
 
+	tableSchemaCacheMu.Lock()
 	tableSchemaCache[tableName] = schema
+	tableSchemaCacheMu.Unlock()
 	return schema, nil
 }
 
+// postgresTimePrecision returns the precision of "timestamp(3) with time zone", 6 by default
+func postgresTimePrecision(dataType string) int {
+	if !strings.HasPrefix(dataType, "time") {
+		return 0
+	}
+	if precision := ExtractFieldTypeLength(dataType); precision > 0 || strings.Contains(dataType, "(0)") {
+		return precision
+	}
+	return 6
+}
+
+// TimeResolution returns the smallest step of the time stored with the precision of fractional second digits
+func TimeResolution(precision int) time.Duration {
+	resolution := time.Second
+	for i := 0; i < precision && i < 9; i++ {
+		resolution /= 10
+	}
+	return resolution
+}
+
+// GetTableSchema returns the columns of the table in the order of their definition
+func GetTableSchema(db *gorm.DB, tableName string) ([]ColumnSchema, error) {
+	return getTableSchema(db, tableName)
+}
+
+// GetTableNames returns the tables of the database
+func GetTableNames(db *gorm.DB) ([]string, error) {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, table := range tables {
+		if strings.HasPrefix(table, "sqlite_") {
+			continue
+		}
+		result = append(result, table)
+	}
+	return result, nil
+}
+
+// GetForeignKeys returns the foreign keys of the table
+func GetForeignKeys(db *gorm.DB, tableName string) ([]ForeignKey, error) {
+	var foreignKeys []ForeignKey
+	dialector := db.Dialector.Name()
+
+	switch dialector {
+	case "mysql":
+		query := `
+			SELECT COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+			WHERE TABLE_SCHEMA = DATABASE()
+			  AND TABLE_NAME = ?
+			  AND REFERENCED_TABLE_NAME IS NOT NULL
+		`
+		type mysqlFk struct {
+			ColumnName           string `gorm:"column:COLUMN_NAME"`
+			ReferencedTableName  string `gorm:"column:REFERENCED_TABLE_NAME"`
+			ReferencedColumnName string `gorm:"column:REFERENCED_COLUMN_NAME"`
+		}
+		var results []mysqlFk
+		if err := db.Raw(query, tableName).Scan(&results).Error; err != nil {
+			return nil, err
+		}
+		for _, fk := range results {
+			foreignKeys = append(foreignKeys, ForeignKey{Column: fk.ColumnName, ReferencedTable: fk.ReferencedTableName, ReferencedColumn: fk.ReferencedColumnName})
+		}
+
+	case "postgres":
+		query := `
+			SELECT kcu.column_name, ccu.table_name AS referenced_table, ccu.column_name AS referenced_column
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+			JOIN information_schema.constraint_column_usage ccu ON ccu.constraint_name = tc.constraint_name AND ccu.table_schema = tc.table_schema
+			WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_name = $1
+		`
+		type pgFk struct {
+			ColumnName       string
+			ReferencedTable  string
+			ReferencedColumn string
+		}
+		var results []pgFk
+		if err := db.Raw(query, tableName).Scan(&results).Error; err != nil {
+			return nil, err
+		}
+		for _, fk := range results {
+			foreignKeys = append(foreignKeys, ForeignKey{Column: fk.ColumnName, ReferencedTable: fk.ReferencedTable, ReferencedColumn: fk.ReferencedColumn})
+		}
+
+	case "sqlite", "sqlite3":
+		type pragmaFk struct {
+			Table string         `gorm:"column:table"`
+			From  string         `gorm:"column:from"`
+			To    sql.NullString `gorm:"column:to"`
+		}
+		var results []pragmaFk
+		query := fmt.Sprintf("PRAGMA foreign_key_list(`%s`);", tableName)
+		if err := db.Raw(query).Scan(&results).Error; err != nil {
+			return nil, err
+		}
+		for _, fk := range results {
+			foreignKeys = append(foreignKeys, ForeignKey{Column: fk.From, ReferencedTable: fk.Table, ReferencedColumn: fk.To.String})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", dialector)
+	}
+
+	return foreignKeys, nil
+}
+
 func getPrimaryKeyFieldNameFromSchema(schema []ColumnSchema) (string, error) {
 	for _, col := range schema {
 		if col.IsPrimaryKey {
@@ -158,22 +306,11 @@ func GetTableColumnTypes(db *gorm.DB, tableName string) (map[string]string, erro
 	return types, nil
 }
 
-func safeString(v any) string {
-	switch val := v.(type) {
-	case nil:
-		return ""
-	case []byte:
-		return string(val)
-	case string:
-		return val
-	case *string:
-		if val != nil {
-			return *val
-		}
-		return ""
-	default:
-		return fmt.Sprint(val)
+func nullableString(v sql.NullString) any {
+	if !v.Valid {
+		return nil
 	}
+	return v.String
 }
 
 func IsLongTextType(fieldType string) bool {
@@ -181,6 +318,22 @@ func IsLongTextType(fieldType string) bool {
 	return strings.Contains(fieldType, "text") || fieldType == "json" || strings.HasPrefix(fieldType, "varchar(") && ExtractFieldTypeLength(fieldType) > 255
 }
 
+// IsDateTimeType reports the columns of date and time: datetime, datetime(6), timestamp, timestamp(3) with time zone...
+func IsDateTimeType(fieldType string) bool {
+	fieldType = strings.ToLower(fieldType)
+	return strings.HasPrefix(fieldType, "datetime") || strings.HasPrefix(fieldType, "timestamp")
+}
+
+// IsTimeType reports the columns of the time of day: time, time(3), time with time zone, timetz
+func IsTimeType(fieldType string) bool {
+	fieldType = strings.ToLower(fieldType)
+	return fieldType == "time" || fieldType == "timetz" || strings.HasPrefix(fieldType, "time(") || strings.HasPrefix(fieldType, "time with")
+}
+
+func IsDateType(fieldType string) bool {
+	return strings.ToLower(fieldType) == "date"
+}
+
 func ExtractFieldTypeLength(fieldType string) int {
 	// Example: varchar(500)
 	start := strings.Index(fieldType, "(")
@@ -324,6 +477,28 @@ func GetTotalRecords(db *gorm.DB, config *model.ConfigOfModel) (int64, error) {
 	return totalRecords, nil
 }
 
+// LastInsertID returns the id of the last inserted record,
+// the query must be executed on the same connection as the insert (e.g. inside a transaction)
+func LastInsertID(db *gorm.DB) (int64, error) {
+	var query string
+	switch db.Dialector.Name() {
+	case "mysql":
+		query = "SELECT LAST_INSERT_ID()"
+	case "postgres":
+		query = "SELECT lastval()"
+	case "sqlite", "sqlite3":
+		query = "SELECT last_insert_rowid()"
+	default:
+		return 0, fmt.Errorf("unsupported database driver: %s", db.Dialector.Name())
+	}
+
+	var id int64
+	if err := db.Raw(query).Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 func IsNumericColumnType(sqlType string) bool {
 	sqlType = strings.ToLower(sqlType)
 
@@ -407,21 +582,34 @@ func ExtractInt64(value any) int64 {
 	return 0
 }
 
+// timeLayouts are the layouts of the time returned by the drivers as text, e.g. MySQL without parseTime
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	"15:04:05",
+}
+
+// ParseTime parses the time returned by the driver as text
+func ParseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 func ExtractFormattedTime(value any, outputFormat string) string {
 	switch v := value.(type) {
 	case time.Time:
 		return v.Format(outputFormat)
 	case string:
-		for _, layout := range []string{
-			time.RFC3339,
-			"2006-01-02 15:04:05",
-			"2006-01-02",
-			"02.01.2006 15:04:05",
-			"02.01.2006",
-		} {
-			if t, err := time.Parse(layout, v); err == nil {
-				return t.Format(outputFormat)
-			}
+		if t, ok := ParseTime(v); ok {
+			return t.Format(outputFormat)
 		}
 		return v // fallback
 	case []byte:
@@ -480,3 +668,68 @@ func ParseRawSql(raw string) (ps ParsedSql, success bool) {
 
 	return ps, true
 }
+
+// GetUniqueColumns returns the columns having a unique index or constraint of their own, the primary key excluded
+func GetUniqueColumns(db *gorm.DB, tableName string) ([]string, error) {
+	var columns []string
+	dialector := db.Dialector.Name()
+
+	switch dialector {
+	case "mysql":
+		query := `
+			SELECT MIN(COLUMN_NAME)
+			FROM INFORMATION_SCHEMA.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE()
+			  AND TABLE_NAME = ?
+			  AND NON_UNIQUE = 0
+			  AND INDEX_NAME <> 'PRIMARY'
+			GROUP BY INDEX_NAME
+			HAVING COUNT(*) = 1
+		`
+		if err := db.Raw(query, tableName).Scan(&columns).Error; err != nil {
+			return nil, err
+		}
+
+	case "postgres":
+		query := `
+			SELECT a.attname
+			FROM pg_index i
+			JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[0]
+			WHERE i.indrelid = $1::regclass
+			  AND i.indisunique
+			  AND NOT i.indisprimary
+			  AND i.indnatts = 1
+		`
+		if err := db.Raw(query, tableName).Scan(&columns).Error; err != nil {
+			return nil, err
+		}
+
+	case "sqlite", "sqlite3":
+		type pragmaIndex struct {
+			Name   string `gorm:"column:name"`
+			Unique bool   `gorm:"column:unique"`
+			Origin string `gorm:"column:origin"`
+		}
+		var indexes []pragmaIndex
+		if err := db.Raw(fmt.Sprintf("PRAGMA index_list(`%s`);", tableName)).Scan(&indexes).Error; err != nil {
+			return nil, err
+		}
+		for _, index := range indexes {
+			if !index.Unique || index.Origin == "pk" {
+				continue
+			}
+			var indexColumns []string
+			if err := db.Raw(fmt.Sprintf("SELECT name FROM pragma_index_info('%s');", index.Name)).Scan(&indexColumns).Error; err != nil {
+				return nil, err
+			}
+			if len(indexColumns) == 1 {
+				columns = append(columns, indexColumns[0])
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", dialector)
+	}
+
+	return columns, nil
+}
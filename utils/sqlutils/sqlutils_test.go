@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var testDBSQLite *gorm.DB
@@ -158,3 +159,40 @@ func TestParseRawSql(t *testing.T) {
 		}
 	}
 }
+
+func TestPostgresTimePrecision(t *testing.T) {
+	tests := map[string]time.Duration{
+		"timestamp without time zone":    time.Microsecond,
+		"timestamp(3) with time zone":    time.Millisecond,
+		"timestamp(0) without time zone": time.Second,
+		"integer":                        time.Second,
+	}
+	for dataType, expected := range tests {
+		if resolution := TimeResolution(postgresTimePrecision(dataType)); resolution != expected {
+			t.Errorf("%s: expected %v, got %v", dataType, expected, resolution)
+		}
+	}
+}
+
+func TestDateTimeTypes(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		dateTime  bool
+		time      bool
+	}{
+		{fieldType: "datetime", dateTime: true},
+		{fieldType: "DATETIME(6)", dateTime: true},
+		{fieldType: "timestamp", dateTime: true},
+		{fieldType: "timestamp(3) with time zone", dateTime: true},
+		{fieldType: "time", time: true},
+		{fieldType: "time(3) without time zone", time: true},
+		{fieldType: "timetz", time: true},
+		{fieldType: "date"},
+		{fieldType: "varchar(255)"},
+	}
+	for _, tt := range tests {
+		if IsDateTimeType(tt.fieldType) != tt.dateTime || IsTimeType(tt.fieldType) != tt.time {
+			t.Errorf("%s: expected datetime %v and time %v", tt.fieldType, tt.dateTime, tt.time)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"regexp"
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -42,6 +43,16 @@ func IsCamelCase(input string) bool {
 	return false
 }
 
+// HumanizeName convert from snake_case or camelCase to "Sentence case" words, e.g. created_at -> Created at
+func HumanizeName(input string) string {
+	words := strings.Fields(strings.ReplaceAll(CamelToSnake(input), "_", " "))
+	if len(words) == 0 {
+		return input
+	}
+	words[0] = cases.Title(language.Und).String(words[0])
+	return strings.Join(words, " ")
+}
+
 func CleanPrefixes(s string, prefixes []string) string {
 	s = strings.TrimSpace(s)
 	upper := strings.ToUpper(s)
@@ -53,3 +64,16 @@ func CleanPrefixes(s string, prefixes []string) string {
 	}
 	return s
 }
+
+// labelFieldCandidates are the column names which usually describe a record, in order of preference
+var labelFieldCandidates = []string{"name", "title", "label", "username", "login", "email", "code", "description"}
+
+// GuessLabelField returns the column which is the most likely human-readable label of a record, or "" if there is no such column
+func GuessLabelField(columns []string) string {
+	for _, candidate := range labelFieldCandidates {
+		if slices.Contains(columns, candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
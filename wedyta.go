@@ -16,3 +16,8 @@ func New(r *gin.Engine, db *gorm.DB, cfg *model.WedytaConfig) *service.Service {
 	c.RegisterRoutes(r)
 	return s
 }
+
+// RegisterModel makes the GORM struct available as a model without a JSON config, see ModelOptions
+func RegisterModel(value interface{}, opts ModelOptions) {
+	service.RegisterModel(value, opts)
+}